@@ -1,21 +1,26 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"net/url"
+	"os"
 
 	"github.com/amacneil/dbmate/v2/pkg/dbmate"
 	_ "github.com/amacneil/dbmate/v2/pkg/driver/postgres"
-	"github.com/amberdance/url-shortener/internal/app"
+	"github.com/amberdance/url-shortener/internal/config"
+	"github.com/joho/godotenv"
 )
 
 func main() {
-	a, err := app.GetApp()
+	_ = godotenv.Load()
+
+	cfg, err := config.Load(flag.CommandLine, os.Args[1:])
 	if err != nil {
-		panic(err)
+		log.Fatalln(err)
 	}
 
-	u, err := url.Parse(a.Config().DatabaseDSN)
+	u, err := url.Parse(cfg.DatabaseDSN)
 	if err != nil {
 		log.Fatalln(err)
 	}
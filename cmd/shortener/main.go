@@ -2,22 +2,41 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/amberdance/url-shortener/internal/app"
-	"github.com/amberdance/url-shortener/internal/ports/webapi"
+	"github.com/amberdance/url-shortener/internal/config"
+	"github.com/amberdance/url-shortener/internal/infrastructure/tracing"
+	"github.com/amberdance/url-shortener/pkg/shortener"
+	"github.com/joho/godotenv"
 )
 
 func main() {
-	a, err := app.GetApp()
+	if err := godotenv.Load(); err != nil {
+		log.Println("не найден файл .env, используются переменные окружения")
+	}
+
+	cfg, err := config.Load(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	s, err := shortener.New(*cfg)
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	defer a.Close()
+	defer s.Close()
+
+	tracing.Install(s.TracerProvider())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	srv := webapi.NewServer(a)
-	if err := srv.Run(context.Background()); err != nil {
+	if err := s.Run(ctx); err != nil {
 		log.Fatalf("server error: %v", err)
 	}
 }
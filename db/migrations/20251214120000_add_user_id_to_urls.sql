@@ -0,0 +1,7 @@
+-- migrate:up
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS user_id uuid NULL;
+CREATE INDEX IF NOT EXISTS urls_user_id_idx ON urls (user_id);
+
+-- migrate:down
+DROP INDEX IF EXISTS urls_user_id_idx;
+ALTER TABLE urls DROP COLUMN IF EXISTS user_id;
@@ -0,0 +1,25 @@
+-- migrate:up
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ NULL;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS max_clicks INTEGER NULL;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS clicks INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS urls_expires_at_idx ON urls (expires_at) WHERE expires_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS urls_archive (
+    id UUID PRIMARY KEY,
+    hash VARCHAR(255) NOT NULL,
+    original_url TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    correlation_id VARCHAR(255) NULL,
+    user_id UUID NULL,
+    expires_at TIMESTAMPTZ NULL,
+    max_clicks INTEGER NULL,
+    clicks INTEGER NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- migrate:down
+DROP TABLE IF EXISTS urls_archive;
+DROP INDEX IF EXISTS urls_expires_at_idx;
+ALTER TABLE urls DROP COLUMN IF EXISTS clicks;
+ALTER TABLE urls DROP COLUMN IF EXISTS max_clicks;
+ALTER TABLE urls DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,13 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS clicks (
+    id BIGSERIAL PRIMARY KEY,
+    url_id UUID NOT NULL REFERENCES urls (id) ON DELETE CASCADE,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    referrer TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip VARCHAR(64) NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS clicks_url_id_occurred_at_idx ON clicks (url_id, occurred_at);
+
+-- migrate:down
+DROP TABLE IF EXISTS clicks;
@@ -0,0 +1,5 @@
+-- migrate:up
+CREATE SEQUENCE IF NOT EXISTS url_hash_seq;
+
+-- migrate:down
+DROP SEQUENCE IF EXISTS url_hash_seq;
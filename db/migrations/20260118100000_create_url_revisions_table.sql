@@ -0,0 +1,13 @@
+-- migrate:up
+CREATE TABLE IF NOT EXISTS url_revisions (
+    id UUID PRIMARY KEY,
+    url_id UUID NOT NULL REFERENCES urls (id) ON DELETE CASCADE,
+    original_url TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NULL,
+    max_clicks INTEGER NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS url_revisions_url_id_created_at_idx ON url_revisions (url_id, created_at);
+
+-- migrate:down
+DROP TABLE IF EXISTS url_revisions;
@@ -0,0 +1,7 @@
+-- migrate:up
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS redirect_code SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS noindex BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- migrate:down
+ALTER TABLE urls DROP COLUMN IF EXISTS noindex;
+ALTER TABLE urls DROP COLUMN IF EXISTS redirect_code;
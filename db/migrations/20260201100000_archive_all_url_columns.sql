@@ -0,0 +1,17 @@
+-- migrate:up
+ALTER TABLE urls_archive ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NULL;
+ALTER TABLE urls_archive ADD COLUMN IF NOT EXISTS is_deleted BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE urls_archive ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+ALTER TABLE urls_archive ADD COLUMN IF NOT EXISTS redirect_code SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE urls_archive ADD COLUMN IF NOT EXISTS noindex BOOLEAN NOT NULL DEFAULT FALSE;
+-- История правок переживает перенос ссылки в архив.
+ALTER TABLE url_revisions DROP CONSTRAINT IF EXISTS url_revisions_url_id_fkey;
+
+-- migrate:down
+DELETE FROM url_revisions WHERE url_id NOT IN (SELECT id FROM urls);
+ALTER TABLE url_revisions ADD CONSTRAINT url_revisions_url_id_fkey FOREIGN KEY (url_id) REFERENCES urls (id) ON DELETE CASCADE;
+ALTER TABLE urls_archive DROP COLUMN IF EXISTS noindex;
+ALTER TABLE urls_archive DROP COLUMN IF EXISTS redirect_code;
+ALTER TABLE urls_archive DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE urls_archive DROP COLUMN IF EXISTS is_deleted;
+ALTER TABLE urls_archive DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,7 @@
+-- migrate:up
+ALTER TABLE urls DROP CONSTRAINT IF EXISTS urls_original_url_key;
+CREATE UNIQUE INDEX IF NOT EXISTS urls_original_url_live_key ON urls (original_url) WHERE NOT is_deleted;
+
+-- migrate:down
+DROP INDEX IF EXISTS urls_original_url_live_key;
+ALTER TABLE urls ADD CONSTRAINT urls_original_url_key UNIQUE (original_url);
@@ -0,0 +1,10 @@
+-- migrate:up
+-- Ссылки с лимитом переходов, по которым уборщик ищет исчерпанные.
+CREATE INDEX IF NOT EXISTS urls_max_clicks_idx ON urls (id) WHERE max_clicks IS NOT NULL;
+-- История переходов переживает перенос ссылки в архив.
+ALTER TABLE clicks DROP CONSTRAINT IF EXISTS clicks_url_id_fkey;
+
+-- migrate:down
+DELETE FROM clicks WHERE url_id NOT IN (SELECT id FROM urls);
+ALTER TABLE clicks ADD CONSTRAINT clicks_url_id_fkey FOREIGN KEY (url_id) REFERENCES urls (id) ON DELETE CASCADE;
+DROP INDEX IF EXISTS urls_max_clicks_idx;
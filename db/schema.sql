@@ -19,6 +19,51 @@ SET default_tablespace = '';
 
 SET default_table_access_method = heap;
 
+--
+-- Name: clicks; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.clicks (
+    id bigint NOT NULL,
+    url_id uuid NOT NULL,
+    occurred_at timestamp with time zone NOT NULL,
+    referrer text DEFAULT ''::text NOT NULL,
+    user_agent text DEFAULT ''::text NOT NULL,
+    ip character varying(64) DEFAULT ''::character varying NOT NULL
+);
+
+
+--
+-- Name: clicks_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.clicks_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: clicks_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.clicks_id_seq OWNED BY public.clicks.id;
+
+
+--
+-- Name: url_hash_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.url_hash_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
 --
 -- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
 --
@@ -28,6 +73,22 @@ CREATE TABLE public.schema_migrations (
 );
 
 
+--
+-- Name: url_revisions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.url_revisions (
+    id uuid NOT NULL,
+    url_id uuid NOT NULL,
+    original_url text NOT NULL,
+    expires_at timestamp with time zone,
+    max_clicks integer,
+    created_at timestamp with time zone NOT NULL,
+    redirect_code smallint DEFAULT 0 NOT NULL,
+    noindex boolean DEFAULT false NOT NULL
+);
+
+
 --
 -- Name: urls; Type: TABLE; Schema: public; Owner: -
 --
@@ -38,10 +99,56 @@ CREATE TABLE public.urls (
     original_url text NOT NULL,
     created_at timestamp with time zone DEFAULT now() NOT NULL,
     updated_at timestamp with time zone,
-    correlation_id character varying(255)
+    correlation_id character varying(255),
+    user_id uuid,
+    is_deleted boolean DEFAULT false NOT NULL,
+    deleted_at timestamp with time zone,
+    expires_at timestamp with time zone,
+    max_clicks integer,
+    clicks integer DEFAULT 0 NOT NULL,
+    redirect_code smallint DEFAULT 0 NOT NULL,
+    noindex boolean DEFAULT false NOT NULL
+);
+
+
+--
+-- Name: urls_archive; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.urls_archive (
+    id uuid NOT NULL,
+    hash character varying(255) NOT NULL,
+    original_url text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    correlation_id character varying(255),
+    user_id uuid,
+    expires_at timestamp with time zone,
+    max_clicks integer,
+    clicks integer NOT NULL,
+    archived_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone,
+    is_deleted boolean DEFAULT false NOT NULL,
+    deleted_at timestamp with time zone,
+    redirect_code smallint DEFAULT 0 NOT NULL,
+    noindex boolean DEFAULT false NOT NULL
 );
 
 
+--
+-- Name: clicks id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.clicks ALTER COLUMN id SET DEFAULT nextval('public.clicks_id_seq'::regclass);
+
+
+--
+-- Name: clicks clicks_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.clicks
+    ADD CONSTRAINT clicks_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -67,19 +174,69 @@ ALTER TABLE ONLY public.urls
 
 
 --
--- Name: urls urls_original_url_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: urls urls_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
 ALTER TABLE ONLY public.urls
-    ADD CONSTRAINT urls_original_url_key UNIQUE (original_url);
+    ADD CONSTRAINT urls_pkey PRIMARY KEY (id);
 
 
 --
--- Name: urls urls_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: url_revisions url_revisions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.urls
-    ADD CONSTRAINT urls_pkey PRIMARY KEY (id);
+ALTER TABLE ONLY public.url_revisions
+    ADD CONSTRAINT url_revisions_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: urls_archive urls_archive_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.urls_archive
+    ADD CONSTRAINT urls_archive_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: clicks_url_id_occurred_at_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX clicks_url_id_occurred_at_idx ON public.clicks USING btree (url_id, occurred_at);
+
+
+--
+-- Name: url_revisions_url_id_created_at_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX url_revisions_url_id_created_at_idx ON public.url_revisions USING btree (url_id, created_at);
+
+
+--
+-- Name: urls_expires_at_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX urls_expires_at_idx ON public.urls USING btree (expires_at) WHERE (expires_at IS NOT NULL);
+
+
+--
+-- Name: urls_max_clicks_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX urls_max_clicks_idx ON public.urls USING btree (id) WHERE (max_clicks IS NOT NULL);
+
+
+--
+-- Name: urls_original_url_live_key; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX urls_original_url_live_key ON public.urls USING btree (original_url) WHERE (NOT is_deleted);
+
+
+--
+-- Name: urls_user_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX urls_user_id_idx ON public.urls USING btree (user_id);
 
 
 --
@@ -94,4 +251,15 @@ ALTER TABLE ONLY public.urls
 --
 
 INSERT INTO public.schema_migrations (version) VALUES
-    ('20251207152312');
+    ('20251207152312'),
+    ('20251214120000'),
+    ('20251221100000'),
+    ('20251228100000'),
+    ('20260104100000'),
+    ('20260111100000'),
+    ('20260118100000'),
+    ('20260125100000'),
+    ('20260201100000'),
+    ('20260208100000'),
+    ('20260215100000'),
+    ('20260222100000');
@@ -1,48 +1,92 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"net/url"
-	"sync"
+	"time"
 
 	"github.com/amacneil/dbmate/v2/pkg/dbmate"
 	"github.com/amberdance/url-shortener/internal/config"
 	"github.com/amberdance/url-shortener/internal/domain/contracts"
 	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/hashgen"
 	"github.com/amberdance/url-shortener/internal/infrastructure/logging"
+	"github.com/amberdance/url-shortener/internal/infrastructure/metrics"
 	"github.com/amberdance/url-shortener/internal/infrastructure/repository"
+	urlrepo "github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
 	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/amberdance/url-shortener/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type App struct {
 	config    *config.Config
 	container *Container
 	logger    shared.Logger
+	clock     shared.Clock
+	provider  RepositoryProvider
 	storage   *storage.PostgresStorage
+	files     *storage.FileStorage
 	pinger    contracts.Pinger
+	hashes    shared.HashGenerator
+	metrics   *metrics.Metrics
+	backend   string
+	urlCache  *urlrepo.CachedURLRepository
+	links     *storage.LinkSubscription
+	// tracerProvider выдаёт tracer'ы репозиториям, pgx и входящим запросам.
+	tracerProvider trace.TracerProvider
+	// stopTracing сбрасывает накопленные span'ы в экспортёр.
+	stopTracing func(context.Context) error
 }
 
-var (
-	instance *App
-	once     sync.Once
-)
+const shutdownTimeout = 10 * time.Second
 
-func GetApp() (*App, error) {
-	var initErr error
+// hashCounterStep — сколько номеров коротких кодов резервируется в файле
+// счётчика за одну запись.
+const hashCounterStep = 1000
 
-	once.Do(func() {
-		app := &App{}
-		if err := app.init(); err != nil {
-			initErr = fmt.Errorf("failed to initialize app: %w", err)
-			return
-		}
-		instance = app
-	})
+type Option func(*App)
+
+// WithLogger подменяет логгер, который иначе строится по cfg.LogLevel.
+func WithLogger(l shared.Logger) Option {
+	return func(a *App) { a.logger = l }
+}
+
+// WithRepositoryProvider отключает выбор хранилища по конфигурации.
+// Если провайдер реализует contracts.Pinger, он же отвечает на /ping.
+func WithRepositoryProvider(p RepositoryProvider) Option {
+	return func(a *App) { a.provider = p }
+}
+
+func WithClock(c shared.Clock) Option {
+	return func(a *App) { a.clock = c }
+}
 
-	if initErr != nil {
-		return nil, initErr
+// WithHashGenerator подменяет генератор коротких кодов, который иначе
+// строится по cfg.HashGenerator.
+func WithHashGenerator(g shared.HashGenerator) Option {
+	return func(a *App) { a.hashes = g }
+}
+
+// WithTracerProvider подменяет провайдер трасс, который иначе строится по
+// cfg.TracingExporter.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(a *App) { a.tracerProvider = tp }
+}
+
+func New(cfg *config.Config, opts ...Option) (*App, error) {
+	a := &App{config: cfg}
+	for _, opt := range opts {
+		opt(a)
 	}
-	return instance, nil
+
+	if err := a.init(); err != nil {
+		a.Close()
+		return nil, fmt.Errorf("failed to initialize app: %w", err)
+	}
+
+	return a, nil
 }
 
 func (a *App) Config() *config.Config { return a.config }
@@ -51,32 +95,148 @@ func (a *App) Container() *Container { return a.container }
 
 func (a *App) Logger() shared.Logger { return a.logger }
 
+func (a *App) Clock() shared.Clock { return a.clock }
+
 func (a *App) Storage() *storage.PostgresStorage { return a.storage }
 
 func (a *App) Pinger() contracts.Pinger { return a.pinger }
 
+func (a *App) Metrics() *metrics.Metrics { return a.metrics }
+
+func (a *App) TracerProvider() trace.TracerProvider { return a.tracerProvider }
+
 func (a *App) Close() {
-	if a.logger != nil {
-		a.logger.Close()
+	if a.container != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := a.container.URLDeleter.Stop(ctx); err != nil && a.logger != nil {
+			a.logger.Error("failed to drain url deleter", "error", err)
+		}
+		if err := a.container.URLJanitor.Stop(ctx); err != nil && a.logger != nil {
+			a.logger.Error("failed to stop url janitor", "error", err)
+		}
+		if err := a.container.ClickRecorder.Stop(ctx); err != nil && a.logger != nil {
+			a.logger.Error("failed to drain click recorder", "error", err)
+		}
+	}
+	if a.stopTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := a.stopTracing(ctx); err != nil && a.logger != nil {
+			a.logger.Error("failed to flush traces", "error", err)
+		}
+	}
+	// Подписка пишет в лог при обрыве, поэтому закрывается раньше логгера.
+	if a.links != nil {
+		a.links.Close()
 	}
 	if a.storage != nil {
 		a.storage.Close()
 	}
+	if a.files != nil {
+		if err := a.files.Close(); err != nil && a.logger != nil {
+			a.logger.Error("failed to close file storage", "error", err)
+		}
+	}
+	if a.logger != nil {
+		a.logger.Close()
+	}
 }
 
 func (a *App) init() error {
-	a.config = config.GetConfig()
-	a.logger = logging.NewLogger()
+	if a.logger == nil {
+		opts := []logging.Option{logging.WithFormat(a.config.LogFormat)}
+		if a.config.LogFile != "" {
+			opts = append(opts, logging.WithFile(a.config.LogFile, logging.Rotation{
+				MaxSize:    int64(a.config.LogMaxSizeMB) << 20,
+				Every:      a.config.LogRotateEvery,
+				MaxBackups: a.config.LogMaxBackups,
+			}))
+		}
+		l, err := logging.NewLogger(a.config.LogLevel, opts...)
+		if err != nil {
+			return err
+		}
+		a.logger = l
+	}
 
-	p, err := a.resolveRepositoryProvider()
-	if err != nil {
-		return err
+	if a.config.SecretKeyGenerated() {
+		a.logger.Info("SECRET_KEY is not set, generated a random one: auth cookies will not survive a restart")
+	}
+
+	if a.clock == nil {
+		a.clock = shared.SystemClock{}
+	}
+
+	if a.tracerProvider == nil {
+		tp, stop, err := tracing.Setup(context.Background(),
+			a.config.TracingExporter, a.config.TracingEndpoint, a.config.TracingFile)
+		if err != nil {
+			return fmt.Errorf("failed to set up tracing: %w", err)
+		}
+		a.tracerProvider = tp
+		a.stopTracing = stop
+	}
+
+	a.metrics = metrics.New()
+
+	if a.provider == nil {
+		p, err := a.resolveRepositoryProvider()
+		if err != nil {
+			return err
+		}
+		a.provider = p
+	} else if pinger, ok := a.provider.(contracts.Pinger); ok {
+		a.pinger = pinger
+		a.backend = "custom"
+	} else {
+		a.pinger = contracts.NopPinger{}
+		a.backend = "custom"
+	}
+	a.provider = repository.NewInstrumentedRepositories(a.provider, a.backend, a.metrics, a.tracerProvider)
+
+	// Об изменениях ссылок сообщается всегда: у соседних экземпляров кэш
+	// может быть включён, даже если у этого он выключен. Файловое хранилище
+	// и хранилище в памяти и так отвечают из памяти.
+	if a.backend == "postgres" {
+		a.provider = repository.ReplaceURLRepository(a.provider,
+			urlrepo.NewNotifyingURLRepository(a.provider.URLRepository(), a.storage, a.logger))
+		if a.config.URLCacheSize > 0 {
+			a.initURLCache()
+		}
 	}
 
-	a.container = buildContainer(p)
+	if a.hashes == nil {
+		g, err := a.resolveHashGenerator()
+		if err != nil {
+			return err
+		}
+		a.hashes = g
+	}
+
+	a.container = buildContainer(a.provider, a.config, a.logger, a.clock, a.hashes)
+	a.container.URLDeleter.Start()
+	a.container.URLJanitor.Start()
+	a.container.ClickRecorder.Start()
 	return nil
 }
 
+// initURLCache ставит кэш перед репозиторием ссылок. Об изменениях
+// экземпляры узнают друг от друга через LISTEN/NOTIFY.
+func (a *App) initURLCache() {
+	a.urlCache = urlrepo.NewCachedURLRepository(a.provider.URLRepository(), urlrepo.CacheOptions{
+		Size:        a.config.URLCacheSize,
+		TTL:         a.config.URLCacheTTL,
+		NegativeTTL: a.config.URLCacheNegativeTTL,
+	})
+	a.metrics.WatchURLCache(a.urlCache)
+	a.provider = repository.ReplaceURLRepository(a.provider, a.urlCache)
+
+	a.links = a.storage.Subscribe(a.urlCache.Apply, func(err error) {
+		a.logger.Error("link events subscription interrupted", "error", err)
+	})
+}
+
 func (a *App) resolveRepositoryProvider() (repository.Provider, error) {
 	if a.config.DatabaseDSN != "" {
 		err := migrateDB(a.config.DatabaseDSN)
@@ -84,27 +244,65 @@ func (a *App) resolveRepositoryProvider() (repository.Provider, error) {
 			return nil, fmt.Errorf("failed to migrate database: %w", err)
 		}
 
-		st, err := storage.NewPostgresStorage(a.config.DatabaseDSN)
+		st, err := storage.NewPostgresStorage(a.config.DatabaseDSN,
+			storage.WithQueryTracer(tracing.NewQueryTracer(a.tracerProvider)))
 		if err != nil {
 			return nil, fmt.Errorf("database connection error: %w", err)
 		}
 		a.storage = st
 		a.pinger = st
+		a.backend = "postgres"
+		a.metrics.WatchPool(st.Pool())
 		return repository.NewRepositories(st), nil
 	}
 
 	if a.config.FileStoragePath != "" {
-		st := storage.NewFileStorage(a.config.FileStoragePath)
+		st, err := storage.NewFileStorage(a.config.FileStoragePath,
+			storage.WithSyncMode(storage.SyncMode(a.config.FileStorageSync)),
+			storage.WithCompaction(a.config.FileStorageCompactThreshold, a.config.FileStorageCompactInterval),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file storage: %w", err)
+		}
+		a.files = st
 		a.pinger = st
+		a.backend = "file"
+		a.metrics.WatchFileStorage(st)
 		return repository.NewFileRepositories(st), nil
 	}
 
 	st := storage.NewInMemoryStorage()
 	a.pinger = st
+	a.backend = "memory"
 
 	return repository.NewMemoryRepositories(st), nil
 }
 
+// resolveHashGenerator строит генератор по конфигурации. Номера для
+// sequence и hashids берутся из url_hash_seq, если данные в PostgreSQL, из
+// счётчика рядом с файлом хранилища, если данные в файле, и из счётчика в
+// памяти, если данные не переживают перезапуск.
+func (a *App) resolveHashGenerator() (shared.HashGenerator, error) {
+	var seq hashgen.SequenceSource
+	switch {
+	case a.storage != nil:
+		seq = hashgen.NewPostgresSequence(a.storage.Pool(), "url_hash_seq")
+	case a.backend == "file":
+		// Файлы прежних версий не хранили счётчик, а номера в них брались из
+		// времени, поэтому новый счётчик начинается с него.
+		c, err := hashgen.NewFileCounter(a.config.FileStoragePath+".seq",
+			uint64(a.clock.Now().UnixMilli()), hashCounterStep)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open hash counter: %w", err)
+		}
+		seq = c
+	default:
+		seq = hashgen.NewCounter(0)
+	}
+
+	return hashgen.New(a.config.HashGenerator, a.config.HashLength, a.config.HashSalt, seq)
+}
+
 func migrateDB(dsn string) error {
 	u, err := url.Parse(dsn)
 	if err != nil {
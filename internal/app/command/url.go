@@ -1,5 +1,12 @@
 package command
 
+import (
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/google/uuid"
+)
+
 type GetURLByHashCommand struct {
 	Hash string
 }
@@ -7,8 +14,70 @@ type GetURLByHashCommand struct {
 type CreateURLEntryCommand struct {
 	CorrelationID *string
 	OriginalURL   string
+	UserID        *uuid.UUID
+	Alias         string
+	ExpiresAt     *time.Time
+	MaxClicks     *int
+	RedirectCode  int
+	NoIndex       bool
 }
 
 type CreateBatchURLEntryCommand struct {
 	Entries []CreateURLEntryCommand
+	UserID  *uuid.UUID
+}
+
+type GetURLsByUserIDCommand struct {
+	UserID uuid.UUID
+}
+
+type DeleteURLsCommand struct {
+	UserID uuid.UUID
+	Hashes []string
+}
+
+type RecordClickCommand struct {
+	URLID     uuid.UUID
+	Referrer  string
+	UserAgent string
+	IP        string
+}
+
+type GetLinkStatsCommand struct {
+	UserID uuid.UUID
+	Hash   string
+}
+
+// ExportURLsCommand выгружает ссылки пользователя UserID или, если он не
+// задан, все ссылки сервиса.
+type ExportURLsCommand struct {
+	UserID *uuid.UUID
+}
+
+// UpdateURLCommand меняет ссылку Hash, принадлежащую UserID.
+type UpdateURLCommand struct {
+	UserID uuid.UUID
+	Hash   string
+	Change model.URLChange
+}
+
+type GetURLRevisionsCommand struct {
+	UserID uuid.UUID
+	Hash   string
+}
+
+// RollbackURLCommand возвращает ссылку Hash к ревизии RevisionID.
+type RollbackURLCommand struct {
+	UserID     uuid.UUID
+	Hash       string
+	RevisionID uuid.UUID
+}
+
+// GetURLInfoCommand ищет ссылку по коду Hash или, если он пуст, по
+// исходному адресу OriginalURL. Если задан UserID, чужие ссылки не
+// находятся.
+type GetURLInfoCommand struct {
+	Hash        string
+	OriginalURL string
+	UserID      *uuid.UUID
 }
@@ -3,28 +3,60 @@ package app
 import (
 	"github.com/amberdance/url-shortener/internal/app/usecase"
 	"github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/app/worker"
+	"github.com/amberdance/url-shortener/internal/config"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
 	"github.com/go-playground/validator/v10"
 )
 
 type Container struct {
 	RepositoryProvider RepositoryProvider
 	Validator          *validator.Validate
+	TokenManager       *auth.TokenManager
+	URLDeleter         *worker.URLDeleter
+	URLJanitor         *worker.URLJanitor
+	ClickRecorder      *worker.ClickRecorder
 	UseCases           struct {
 		URL usecase.URLUseCases
 	}
 }
 
-func buildContainer(r RepositoryProvider) *Container {
+func buildContainer(r RepositoryProvider, cfg *config.Config, l shared.Logger, c shared.Clock, g shared.HashGenerator) *Container {
+	deleter := worker.NewURLDeleter(r.URLRepository(), l)
+	recorder := worker.NewClickRecorder(r.ClickRepository(), l)
+	aliases := model.AliasPolicy{
+		Charset:   cfg.AliasCharset,
+		MinLength: cfg.AliasMinLength,
+		MaxLength: cfg.AliasMaxLength,
+		Reserved:  cfg.ReservedAliases,
+	}
+
 	return &Container{
 		RepositoryProvider: r,
 		Validator:          validator.New(),
+		TokenManager:       auth.NewTokenManager(cfg.SecretKey),
+		URLDeleter:         deleter,
+		URLJanitor:         worker.NewURLJanitor(r.URLRepository(), c, l, cfg.JanitorInterval),
+		ClickRecorder:      recorder,
 		UseCases: struct {
 			URL usecase.URLUseCases
 		}{
 			URL: usecase.URLUseCases{
-				Create:      url.NewCreateURLUseCase(r.URLRepository()),
-				CreateBatch: url.NewBatchCreateURLUseCase(r.URLRepository()),
-				GetByURL:    url.NewGetByHashUseCase(r.URLRepository()),
+				Create:       url.NewCreateURLUseCase(r.URLRepository(), c, aliases, g),
+				CreateBatch:  url.NewBatchCreateURLUseCase(r.URLRepository(), c, aliases, g),
+				GetByURL:     url.NewGetByHashUseCase(r.URLRepository(), c),
+				GetInfo:      url.NewGetURLInfoUseCase(r.URLRepository(), r.ClickRepository(), c),
+				GetByUserID:  url.NewGetByUserIDUseCase(r.URLRepository()),
+				DeleteBatch:  url.NewDeleteBatchUseCase(deleter),
+				GetStats:     url.NewGetStatsUseCase(r.URLRepository()),
+				RecordClick:  url.NewRecordClickUseCase(recorder, c),
+				GetLinkStats: url.NewGetLinkStatsUseCase(r.URLRepository(), r.ClickRepository(), c),
+				Export:       url.NewExportURLsUseCase(r.URLRepository(), r.ClickRepository()),
+				Update:       url.NewUpdateURLUseCase(r.URLRepository(), c),
+				Revisions:    url.NewGetURLRevisionsUseCase(r.URLRepository()),
+				Rollback:     url.NewRollbackURLUseCase(r.URLRepository(), c),
 			},
 		},
 	}
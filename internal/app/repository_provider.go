@@ -4,4 +4,5 @@ import "github.com/amberdance/url-shortener/internal/domain/repository"
 
 type RepositoryProvider interface {
 	URLRepository() repository.URLRepository
+	ClickRepository() repository.ClickRepository
 }
@@ -0,0 +1,28 @@
+// Package telemetry завершает span'ы по правилам, общим для сценариев и
+// обёрток хранилища: доменные ошибки — штатный исход, а не сбой.
+package telemetry
+
+import (
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrorIDKey — атрибут span'а с идентификатором доменной ошибки.
+var ErrorIDKey = attribute.Key("shortener.error_id")
+
+// EndSpan завершает span. Ошибкой помечаются только внутренние сбои:
+// «не найдено», занятый код и другие доменные ошибки — штатный исход, для
+// них записывается событие и атрибут shortener.error_id.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if id := errs.ID(err); id == "internal_error" {
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			span.SetAttributes(ErrorIDKey.String(id))
+		}
+	}
+	span.End()
+}
@@ -3,7 +3,17 @@ package usecase
 import "github.com/amberdance/url-shortener/internal/app/usecase/url"
 
 type URLUseCases struct {
-	GetByURL    url.GetByHashUseCase
-	Create      url.CreateUseCase
-	CreateBatch url.BatchCreateURLUseCase
+	GetByURL     url.GetByHashUseCase
+	GetInfo      url.GetURLInfoUseCase
+	Create       url.CreateUseCase
+	CreateBatch  url.BatchCreateURLUseCase
+	GetByUserID  url.GetByUserIDUseCase
+	DeleteBatch  url.DeleteBatchUseCase
+	GetStats     url.GetStatsUseCase
+	RecordClick  url.RecordClickUseCase
+	GetLinkStats url.GetLinkStatsUseCase
+	Export       url.ExportURLsUseCase
+	Update       url.UpdateURLUseCase
+	Revisions    url.GetURLRevisionsUseCase
+	Rollback     url.RollbackURLUseCase
 }
@@ -0,0 +1,43 @@
+package url
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+)
+
+// maxHashAttempts ограничивает число попыток подобрать свободный код.
+const maxHashAttempts = 5
+
+// nextHash возвращает пользовательский код, если он задан и допустим,
+// иначе просит генератор о новом коде.
+func nextHash(ctx context.Context, g shared.HashGenerator, p model.AliasPolicy, alias, originalURL string, attempt int) (string, error) {
+	if alias == "" {
+		return g.Generate(ctx, originalURL, attempt)
+	}
+
+	if err := p.Validate(alias); err != nil {
+		return "", err
+	}
+
+	return alias, nil
+}
+
+// aliasConflict объясняет, почему код m.Hash, заданный пользователем, не
+// удалось занять: если им уже сокращён тот же адрес, возвращает эту ссылку
+// с DuplicateEntryError, иначе — AliasTakenError.
+func aliasConflict(ctx context.Context, r repository.URLRepository, m *model.URL) (*model.URL, error) {
+	taken, _ := r.FindByHash(ctx, m.Hash)
+	if taken != nil && taken.OriginalURL == m.OriginalURL {
+		return taken, errs.DuplicateEntryError("url already exists")
+	}
+	return nil, errs.AliasTakenError(fmt.Sprintf("alias %q is already taken", m.Hash))
+}
+
+func errHashExhausted() error {
+	return errs.InternalError(fmt.Sprintf("failed to generate a unique short code in %d attempts", maxHashAttempts))
+}
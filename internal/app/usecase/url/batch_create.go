@@ -2,35 +2,261 @@ package url
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
 	"github.com/amberdance/url-shortener/internal/domain/model"
 	"github.com/amberdance/url-shortener/internal/domain/repository"
-	"github.com/amberdance/url-shortener/internal/infrastructure/helpers"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
 )
 
 type BatchCreateURLUseCase struct {
-	repo repository.URLRepository
+	repo    repository.URLRepository
+	clock   shared.Clock
+	aliases model.AliasPolicy
+	hashes  shared.HashGenerator
 }
 
-func NewBatchCreateURLUseCase(r repository.URLRepository) BatchCreateURLUseCase {
-	return BatchCreateURLUseCase{repo: r}
+func NewBatchCreateURLUseCase(r repository.URLRepository, c shared.Clock, p model.AliasPolicy, g shared.HashGenerator) BatchCreateURLUseCase {
+	return BatchCreateURLUseCase{repo: r, clock: c, aliases: p, hashes: g}
 }
 
-func (uc *BatchCreateURLUseCase) Run(ctx context.Context, cmd command.CreateBatchURLEntryCommand) ([]*model.URL, error) {
-	var urls []*model.URL
-	for _, e := range cmd.Entries {
-		m, err := model.NewURL(e.OriginalURL, helpers.GenerateHash(), e.CorrelationID)
-		if err != nil {
+// Run сокращает адреса пачкой и возвращает итог по каждой записи в порядке
+// cmd.Entries. Ошибка в одной записи не мешает сохранить остальные; общая
+// ошибка возвращается, только если не удалось обратиться к репозиторию или
+// генератору кодов.
+func (uc *BatchCreateURLUseCase) Run(ctx context.Context, cmd command.CreateBatchURLEntryCommand) (_ []model.BatchResult, err error) {
+	ctx, span := startSpan(ctx, "CreateURLBatch")
+	span.SetAttributes(batchSizeKey.Int(len(cmd.Entries)))
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	b := &batch{
+		uc:      uc,
+		results: make([]model.BatchResult, len(cmd.Entries)),
+		urls:    make([]*model.URL, len(cmd.Entries)),
+		primary: make(map[int]int),
+		aliased: make(map[int]struct{}),
+		used:    make(map[string]struct{}),
+	}
+
+	if err := b.build(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; len(b.pending) > 0 && attempt <= maxHashAttempts; attempt++ {
+		if err := b.insert(ctx, attempt); err != nil {
 			return nil, err
 		}
+	}
+	for _, i := range b.pending {
+		b.invalid(i, errHashExhausted())
+	}
 
-		urls = append(urls, m)
+	// Повтор адреса внутри пачки получает ту же ссылку, что и первая запись.
+	for i, j := range b.primary {
+		b.results[i].Status = b.results[j].Status
+		b.results[i].URL = b.results[j].URL
+		b.results[i].Err = b.results[j].Err
+		if b.results[i].Status == model.BatchCreated {
+			b.results[i].Status = model.BatchExisting
+		}
 	}
 
-	if err := uc.repo.CreateBatch(ctx, urls); err != nil {
-		return nil, err
+	return b.results, nil
+}
+
+// batch хранит состояние одного вызова Run.
+type batch struct {
+	uc      *BatchCreateURLUseCase
+	results []model.BatchResult
+	urls    []*model.URL
+	// pending — индексы записей, которые ещё нужно сохранить.
+	pending []int
+	// primary связывает повтор адреса внутри пачки с первой записью.
+	primary map[int]int
+	// aliased — записи с пользовательским кодом.
+	aliased map[int]struct{}
+	// used — коды, уже выданные записям пачки.
+	used map[string]struct{}
+	// purged — истёкшие ссылки уже убирались, см. insert.
+	purged bool
+}
+
+func (b *batch) build(ctx context.Context, cmd command.CreateBatchURLEntryCommand) error {
+	now := b.uc.clock.Now()
+	originals := make(map[string]int, len(cmd.Entries))
+
+	for i, e := range cmd.Entries {
+		b.results[i].CorrelationID = e.CorrelationID
+
+		original := strings.TrimSpace(e.OriginalURL)
+		if j, ok := originals[original]; ok && original != "" {
+			b.primary[i] = j
+			continue
+		}
+
+		var hash string
+		if e.Alias != "" {
+			if _, ok := b.used[e.Alias]; ok {
+				b.invalid(i, errs.ValidationError(fmt.Sprintf("alias %q is used more than once", e.Alias)))
+				continue
+			}
+			if err := b.uc.aliases.Validate(e.Alias); err != nil {
+				b.invalid(i, err)
+				continue
+			}
+			hash = e.Alias
+		}
+
+		m, err := model.NewURL(original, "pending", e.CorrelationID, cmd.UserID, now)
+		if err == nil {
+			err = m.SetLimits(e.ExpiresAt, e.MaxClicks, now)
+		}
+		if err == nil {
+			err = m.SetRedirect(e.RedirectCode, e.NoIndex)
+		}
+		if err != nil {
+			b.invalid(i, err)
+			continue
+		}
+
+		originals[original] = i
+		b.urls[i] = m
+		b.pending = append(b.pending, i)
+
+		if hash != "" {
+			m.Hash = hash
+			b.used[hash] = struct{}{}
+			b.aliased[i] = struct{}{}
+		}
+	}
+
+	pending := b.pending[:0]
+	for _, i := range b.pending {
+		if _, ok := b.aliased[i]; !ok {
+			ok, err := b.generate(ctx, i, 0)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+		pending = append(pending, i)
+	}
+	b.pending = pending
+	return nil
+}
+
+// insert сохраняет ожидающие записи и оставляет в pending только те, чей
+// сгенерированный код оказался занят.
+func (b *batch) insert(ctx context.Context, attempt int) error {
+	urls := make([]*model.URL, 0, len(b.pending))
+	for _, i := range b.pending {
+		urls = append(urls, b.urls[i])
 	}
 
-	return urls, nil
+	itemErrs, err := b.uc.repo.CreateBatch(ctx, urls)
+	if err != nil {
+		return err
+	}
+
+	now := b.uc.clock.Now()
+	var retry, expired []int
+	for k, i := range b.pending {
+		m := b.urls[i]
+
+		var (
+			dup          errs.DuplicateEntryError
+			hashConflict errs.HashConflictError
+		)
+		switch {
+		case itemErrs[k] == nil:
+			b.results[i].Status = model.BatchCreated
+			b.results[i].URL = m
+		case errors.As(itemErrs[k], &dup):
+			existed, _ := b.uc.repo.FindByOriginalURL(ctx, m.OriginalURL)
+			if existed == nil {
+				b.invalid(i, dup)
+				continue
+			}
+			if !b.purged && existed.IsExpired(now) {
+				expired = append(expired, i)
+				continue
+			}
+			b.results[i].Status = model.BatchExisting
+			b.results[i].URL = existed
+		case errors.As(itemErrs[k], &hashConflict):
+			if _, ok := b.aliased[i]; ok {
+				existed, aliasErr := aliasConflict(ctx, b.uc.repo, m)
+				if existed == nil {
+					b.invalid(i, aliasErr)
+					continue
+				}
+				b.results[i].Status = model.BatchExisting
+				b.results[i].URL = existed
+				continue
+			}
+			if attempt == maxHashAttempts {
+				retry = append(retry, i)
+				continue
+			}
+			ok, err := b.generate(ctx, i, attempt)
+			if err != nil {
+				return err
+			}
+			if ok {
+				retry = append(retry, i)
+			}
+		default:
+			b.invalid(i, itemErrs[k])
+		}
+	}
+
+	// Адреса истёкших ссылок освобождаются сразу, как и в CreateUseCase.
+	if len(expired) > 0 {
+		if _, err := b.uc.repo.PurgeExpired(ctx, now); err != nil {
+			return err
+		}
+		b.purged = true
+		retry = append(retry, expired...)
+	}
+
+	b.pending = retry
+	return nil
+}
+
+// generate выдаёт записи i новый код, не совпадающий с кодами других
+// записей пачки. Если подобрать код не удалось, запись помечается
+// недопустимой и возвращается false.
+func (b *batch) generate(ctx context.Context, i, attempt int) (bool, error) {
+	m := b.urls[i]
+	if attempt > 0 {
+		delete(b.used, m.Hash)
+	}
+
+	for try := 0; try < maxHashAttempts; try++ {
+		hash, err := b.uc.hashes.Generate(ctx, m.OriginalURL, attempt+try*maxHashAttempts)
+		if err != nil {
+			return false, err
+		}
+		if _, taken := b.used[hash]; taken {
+			continue
+		}
+		b.used[hash] = struct{}{}
+		m.Hash = hash
+		return true, nil
+	}
+
+	b.invalid(i, errHashExhausted())
+	return false, nil
+}
+
+func (b *batch) invalid(i int, err error) {
+	b.results[i].Status = model.BatchInvalid
+	b.results[i].Err = err
 }
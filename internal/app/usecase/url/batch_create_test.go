@@ -0,0 +1,120 @@
+package url_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	urlusecase "github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/hashgen"
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+)
+
+func entries(originals ...string) []command.CreateURLEntryCommand {
+	res := make([]command.CreateURLEntryCommand, 0, len(originals))
+	for _, o := range originals {
+		id := o
+		res = append(res, command.CreateURLEntryCommand{OriginalURL: o, CorrelationID: &id})
+	}
+	return res
+}
+
+func TestBatchCreateUseCase_Run_PartialSuccess(t *testing.T) {
+	ctx := context.Background()
+	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	policy := model.AliasPolicy{Charset: "abcdefghijklmnopqrstuvwxyz", MinLength: 3, MaxLength: 16}
+	uc := urlusecase.NewBatchCreateURLUseCase(repo, shared.SystemClock{}, policy, hashgen.NewRandomGenerator(8))
+
+	known, err := uc.Run(ctx, command.CreateBatchURLEntryCommand{Entries: entries("https://known.example")})
+	require.NoError(t, err)
+	require.Equal(t, model.BatchCreated, known[0].Status)
+
+	cmd := command.CreateBatchURLEntryCommand{Entries: entries(
+		"https://new.example",
+		"https://known.example",
+		"",
+		"https://new.example",
+		"https://alias.example",
+		"https://other.example",
+	)}
+	cmd.Entries[4].Alias = "promo"
+	cmd.Entries[5].Alias = "promo"
+
+	results, err := uc.Run(ctx, cmd)
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+
+	assert.Equal(t, model.BatchCreated, results[0].Status)
+	assert.Equal(t, model.BatchExisting, results[1].Status)
+	assert.Equal(t, known[0].URL.Hash, results[1].URL.Hash)
+	assert.Equal(t, model.BatchInvalid, results[2].Status)
+	assert.ErrorAs(t, results[2].Err, new(errs.ValidationError))
+	assert.Equal(t, model.BatchExisting, results[3].Status, "повтор адреса в пачке")
+	assert.Equal(t, results[0].URL.Hash, results[3].URL.Hash)
+	assert.Equal(t, model.BatchCreated, results[4].Status)
+	assert.Equal(t, "promo", results[4].URL.Hash)
+	assert.Equal(t, model.BatchInvalid, results[5].Status)
+
+	for i, r := range results {
+		assert.Equal(t, cmd.Entries[i].CorrelationID, r.CorrelationID)
+	}
+
+	count, _ := repo.CountURLs(ctx)
+	assert.Equal(t, 3, count)
+}
+
+func TestBatchCreateUseCase_Run_AliasTaken(t *testing.T) {
+	ctx := context.Background()
+	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	policy := model.AliasPolicy{Charset: "abcdefghijklmnopqrstuvwxyz", MinLength: 3, MaxLength: 16}
+	uc := urlusecase.NewBatchCreateURLUseCase(repo, shared.SystemClock{}, policy, hashgen.NewRandomGenerator(8))
+
+	first := command.CreateBatchURLEntryCommand{Entries: entries("https://a.example")}
+	first.Entries[0].Alias = "promo"
+	_, err := uc.Run(ctx, first)
+	require.NoError(t, err)
+
+	second := command.CreateBatchURLEntryCommand{Entries: entries("https://b.example", "https://c.example")}
+	second.Entries[0].Alias = "promo"
+	results, err := uc.Run(ctx, second)
+	require.NoError(t, err)
+
+	assert.Equal(t, model.BatchInvalid, results[0].Status)
+	assert.ErrorAs(t, results[0].Err, new(errs.AliasTakenError))
+	assert.Equal(t, model.BatchCreated, results[1].Status)
+}
+
+func TestBatchCreateUseCase_Run_DedupesGeneratedHashes(t *testing.T) {
+	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	g := &stubGenerator{hashes: []string{"same", "same", "other"}}
+	uc := urlusecase.NewBatchCreateURLUseCase(repo, shared.SystemClock{}, model.AliasPolicy{}, g)
+
+	results, err := uc.Run(context.Background(), command.CreateBatchURLEntryCommand{Entries: entries("https://a.example", "https://b.example")})
+	require.NoError(t, err)
+	assert.Equal(t, "same", results[0].URL.Hash)
+	assert.Equal(t, "other", results[1].URL.Hash)
+}
+
+func TestBatchCreateUseCase_Run_RetriesHashConflict(t *testing.T) {
+	ctx := context.Background()
+	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	g := &stubGenerator{hashes: []string{"taken"}}
+	uc := urlusecase.NewBatchCreateURLUseCase(repo, shared.SystemClock{}, model.AliasPolicy{}, g)
+
+	_, err := uc.Run(ctx, command.CreateBatchURLEntryCommand{Entries: entries("https://a.example")})
+	require.NoError(t, err)
+
+	g.hashes = []string{"taken", "free"}
+	g.calls = 0
+	results, err := uc.Run(ctx, command.CreateBatchURLEntryCommand{Entries: entries("https://b.example")})
+	require.NoError(t, err)
+	assert.Equal(t, model.BatchCreated, results[0].Status)
+	assert.Equal(t, "free", results[0].URL.Hash)
+}
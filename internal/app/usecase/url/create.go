@@ -5,28 +5,62 @@ import (
 	"errors"
 
 	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
 	"github.com/amberdance/url-shortener/internal/domain/errs"
 	"github.com/amberdance/url-shortener/internal/domain/model"
 	"github.com/amberdance/url-shortener/internal/domain/repository"
-	"github.com/amberdance/url-shortener/internal/infrastructure/helpers"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
 )
 
 type CreateUseCase struct {
 	repository repository.URLRepository
+	clock      shared.Clock
+	aliases    model.AliasPolicy
+	hashes     shared.HashGenerator
 }
 
-func NewCreateURLUseCase(r repository.URLRepository) CreateUseCase {
-	return CreateUseCase{repository: r}
+func NewCreateURLUseCase(r repository.URLRepository, c shared.Clock, p model.AliasPolicy, g shared.HashGenerator) CreateUseCase {
+	return CreateUseCase{repository: r, clock: c, aliases: p, hashes: g}
 }
 
-func (uc CreateUseCase) Run(ctx context.Context, cmd command.CreateURLEntryCommand) (*model.URL, error) {
-	m, err := model.NewURL(cmd.OriginalURL, helpers.GenerateHash(), cmd.CorrelationID)
-	if err != nil {
-		return nil, err
-	}
+func (uc CreateUseCase) Run(ctx context.Context, cmd command.CreateURLEntryCommand) (_ *model.URL, err error) {
+	ctx, span := startSpan(ctx, "CreateURL")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	now := uc.clock.Now()
+	purged := false
+
+	for attempt := 0; attempt < maxHashAttempts; attempt++ {
+		hash, err := nextHash(ctx, uc.hashes, uc.aliases, cmd.Alias, cmd.OriginalURL, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		m, err := model.NewURL(cmd.OriginalURL, hash, cmd.CorrelationID, cmd.UserID, now)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = m.SetLimits(cmd.ExpiresAt, cmd.MaxClicks, now); err != nil {
+			return nil, err
+		}
+		if err = m.SetRedirect(cmd.RedirectCode, cmd.NoIndex); err != nil {
+			return nil, err
+		}
+
+		err = uc.repository.Create(ctx, m)
+		if err == nil {
+			return m, nil
+		}
+
+		var hashConflict errs.HashConflictError
+		if errors.As(err, &hashConflict) {
+			if cmd.Alias != "" {
+				return aliasConflict(ctx, uc.repository, m)
+			}
+			continue
+		}
 
-	err = uc.repository.Create(ctx, m)
-	if err != nil {
 		var dup errs.DuplicateEntryError
 		if errors.As(err, &dup) {
 			existed, findErr := uc.repository.FindByOriginalURL(ctx, m.OriginalURL)
@@ -36,10 +70,20 @@ func (uc CreateUseCase) Run(ctx context.Context, cmd command.CreateURLEntryComma
 			if existed == nil {
 				return nil, errs.NotFoundError("URL not found")
 			}
+			// Истёкшая ссылка держит адрес, пока её не убрал janitor; не
+			// дожидаясь его, убираем её сейчас и сохраняем адрес заново.
+			if !purged && existed.IsExpired(now) {
+				if _, err := uc.repository.PurgeExpired(ctx, now); err != nil {
+					return nil, err
+				}
+				purged = true
+				continue
+			}
 			return existed, dup
 		}
+
 		return nil, err
 	}
 
-	return m, nil
+	return nil, errHashExhausted()
 }
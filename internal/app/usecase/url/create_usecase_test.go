@@ -2,18 +2,26 @@ package url_test
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
-
-	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
-	"github.com/stretchr/testify/assert"
+	"time"
 
 	"github.com/amberdance/url-shortener/internal/app/command"
 	urlusecase "github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/hashgen"
 	"github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateUseCase_Run_Success(t *testing.T) {
-	uc := urlusecase.NewCreateURLUseCase(url.NewInMemoryURLRepository(storage.NewInMemoryStorage()))
+	uc := urlusecase.NewCreateURLUseCase(url.NewInMemoryURLRepository(storage.NewInMemoryStorage()), shared.SystemClock{}, model.AliasPolicy{}, hashgen.NewRandomGenerator(8))
 	cmd := command.CreateURLEntryCommand{
 		OriginalURL: "https://hard2code.ru",
 	}
@@ -23,3 +31,102 @@ func TestCreateUseCase_Run_Success(t *testing.T) {
 	assert.Equal(t, cmd.OriginalURL, m.OriginalURL)
 	assert.NotEmpty(t, m.Hash)
 }
+
+func TestCreateUseCase_Run_Alias(t *testing.T) {
+	policy := model.AliasPolicy{
+		Charset:   "abcdefghijklmnopqrstuvwxyz0123456789-_",
+		MinLength: 3,
+		MaxLength: 16,
+		Reserved:  []string{"api"},
+	}
+	uc := urlusecase.NewCreateURLUseCase(url.NewInMemoryURLRepository(storage.NewInMemoryStorage()), shared.SystemClock{}, policy, hashgen.NewRandomGenerator(8))
+
+	m, err := uc.Run(context.Background(), command.CreateURLEntryCommand{OriginalURL: "https://hard2code.ru", Alias: "promo2026"})
+	assert.NoError(t, err)
+	assert.Equal(t, "promo2026", m.Hash)
+
+	_, err = uc.Run(context.Background(), command.CreateURLEntryCommand{OriginalURL: "https://example.com", Alias: "promo2026"})
+	assert.ErrorAs(t, err, new(errs.AliasTakenError))
+
+	_, err = uc.Run(context.Background(), command.CreateURLEntryCommand{OriginalURL: "https://example.com", Alias: "Api"})
+	assert.ErrorAs(t, err, new(errs.ValidationError))
+}
+
+// stubGenerator выдаёт коды из списка по очереди.
+type stubGenerator struct {
+	hashes []string
+	calls  int
+}
+
+func (g *stubGenerator) Generate(_ context.Context, _ string, _ int) (string, error) {
+	h := g.hashes[g.calls%len(g.hashes)]
+	g.calls++
+	return h, nil
+}
+
+func TestCreateUseCase_Run_RetriesOnHashConflict(t *testing.T) {
+	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	g := &stubGenerator{hashes: []string{"taken", "taken", "free"}}
+	uc := urlusecase.NewCreateURLUseCase(repo, shared.SystemClock{}, model.AliasPolicy{}, g)
+
+	first, err := uc.Run(context.Background(), command.CreateURLEntryCommand{OriginalURL: "https://a.example"})
+	assert.NoError(t, err)
+	assert.Equal(t, "taken", first.Hash)
+
+	g.calls = 0
+	second, err := uc.Run(context.Background(), command.CreateURLEntryCommand{OriginalURL: "https://b.example"})
+	assert.NoError(t, err)
+	assert.Equal(t, "free", second.Hash)
+	assert.Equal(t, 3, g.calls)
+}
+
+func TestCreateUseCase_Run_GivesUpOnHashConflict(t *testing.T) {
+	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	g := &stubGenerator{hashes: []string{"taken"}}
+	uc := urlusecase.NewCreateURLUseCase(repo, shared.SystemClock{}, model.AliasPolicy{}, g)
+
+	_, err := uc.Run(context.Background(), command.CreateURLEntryCommand{OriginalURL: "https://a.example"})
+	assert.NoError(t, err)
+
+	_, err = uc.Run(context.Background(), command.CreateURLEntryCommand{OriginalURL: "https://b.example"})
+	assert.ErrorAs(t, err, new(errs.InternalError))
+}
+
+func TestCreateUseCase_Run_ReshortensDeadURL(t *testing.T) {
+	ctx := context.Background()
+	file, err := storage.NewFileStorage(filepath.Join(t.TempDir(), "db.json"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	repos := map[string]repository.URLRepository{
+		"memory": url.NewInMemoryURLRepository(storage.NewInMemoryStorage()),
+		"file":   url.NewFileURLRepository(file),
+	}
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			userID := uuid.New()
+			now := time.Now()
+			expiresAt := now.Add(time.Hour)
+			create := urlusecase.NewCreateURLUseCase(repo, fixedClock(now), model.AliasPolicy{}, hashgen.NewRandomGenerator(8))
+
+			deleted, err := create.Run(ctx, command.CreateURLEntryCommand{OriginalURL: "https://deleted.example", UserID: &userID})
+			require.NoError(t, err)
+			require.NoError(t, repo.DeleteBatch(ctx, map[uuid.UUID][]string{userID: []string{deleted.Hash}}))
+
+			again, err := create.Run(ctx, command.CreateURLEntryCommand{OriginalURL: "https://deleted.example", UserID: &userID})
+			require.NoError(t, err)
+			assert.NotEqual(t, deleted.Hash, again.Hash)
+
+			_, err = create.Run(ctx, command.CreateURLEntryCommand{OriginalURL: "https://deleted.example"})
+			assert.ErrorAs(t, err, new(errs.DuplicateEntryError), "живая ссылка по-прежнему держит адрес")
+
+			expired, err := create.Run(ctx, command.CreateURLEntryCommand{OriginalURL: "https://expired.example", ExpiresAt: &expiresAt})
+			require.NoError(t, err)
+
+			later := urlusecase.NewCreateURLUseCase(repo, fixedClock(expiresAt), model.AliasPolicy{}, hashgen.NewRandomGenerator(8))
+			renewed, err := later.Run(ctx, command.CreateURLEntryCommand{OriginalURL: "https://expired.example"})
+			require.NoError(t, err)
+			assert.NotEqual(t, expired.Hash, renewed.Hash)
+		})
+	}
+}
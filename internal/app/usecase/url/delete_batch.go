@@ -0,0 +1,32 @@
+package url
+
+import (
+	"context"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+)
+
+type DeleteQueue interface {
+	Enqueue(ctx context.Context, cmd command.DeleteURLsCommand) error
+}
+
+type DeleteBatchUseCase struct {
+	queue DeleteQueue
+}
+
+func NewDeleteBatchUseCase(q DeleteQueue) DeleteBatchUseCase {
+	return DeleteBatchUseCase{queue: q}
+}
+
+func (uc DeleteBatchUseCase) Run(ctx context.Context, cmd command.DeleteURLsCommand) (err error) {
+	ctx, span := startSpan(ctx, "DeleteURLs")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	if len(cmd.Hashes) == 0 {
+		return errs.ValidationError("Не передано ни одного хэша")
+	}
+
+	return uc.queue.Enqueue(ctx, cmd)
+}
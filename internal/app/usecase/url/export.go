@@ -0,0 +1,65 @@
+package url
+
+import (
+	"context"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+// exportPageSize — сколько ссылок читается из репозитория за раз.
+const exportPageSize = 1000
+
+type ExportURLsUseCase struct {
+	urls   repository.URLRepository
+	clicks repository.ClickRepository
+}
+
+func NewExportURLsUseCase(u repository.URLRepository, c repository.ClickRepository) ExportURLsUseCase {
+	return ExportURLsUseCase{urls: u, clicks: c}
+}
+
+// Run передаёт ссылки в emit страницами в порядке возрастания ID, так что
+// в памяти одновременно находится не больше одной страницы. Ошибка emit
+// прерывает выгрузку.
+func (uc ExportURLsUseCase) Run(ctx context.Context, cmd command.ExportURLsCommand, emit func([]model.ExportedURL) error) (err error) {
+	ctx, span := startSpan(ctx, "ExportURLs")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	q := model.URLPageQuery{UserID: cmd.UserID, Limit: exportPageSize}
+
+	for {
+		page, err := uc.urls.FindPage(ctx, q)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, 0, len(page))
+		for _, u := range page {
+			ids = append(ids, u.ID)
+		}
+		counts, err := uc.clicks.CountByURL(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		exported := make([]model.ExportedURL, 0, len(page))
+		for _, u := range page {
+			exported = append(exported, model.ExportedURL{URL: u, TotalClicks: counts[u.ID]})
+		}
+		if err := emit(exported); err != nil {
+			return err
+		}
+
+		if len(page) < q.Limit {
+			return nil
+		}
+		q.After = page[len(page)-1].ID
+	}
+}
@@ -4,18 +4,47 @@ import (
 	"context"
 
 	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
 	"github.com/amberdance/url-shortener/internal/domain/model"
 	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
 )
 
 type GetByHashUseCase struct {
 	repository repository.URLRepository
+	clock      shared.Clock
 }
 
-func NewGetByHashUseCase(r repository.URLRepository) GetByHashUseCase {
-	return GetByHashUseCase{repository: r}
+func NewGetByHashUseCase(r repository.URLRepository, c shared.Clock) GetByHashUseCase {
+	return GetByHashUseCase{repository: r, clock: c}
 }
 
-func (uc GetByHashUseCase) Run(ctx context.Context, cmd command.GetURLByHashCommand) (*model.URL, error) {
-	return uc.repository.FindByHash(ctx, cmd.Hash)
+func (uc GetByHashUseCase) Run(ctx context.Context, cmd command.GetURLByHashCommand) (_ *model.URL, err error) {
+	ctx, span := startSpan(ctx, "GetURLByHash")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	m, err := uc.repository.FindByHash(ctx, cmd.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	switch m.Status(uc.clock.Now()) {
+	case model.URLDeleted:
+		return nil, errs.GoneError("url deleted")
+	case model.URLExpired:
+		return nil, errs.GoneError("url expired")
+	}
+
+	if m.MaxClicks != nil {
+		ok, err := uc.repository.RegisterClick(ctx, m.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errs.GoneError("url expired")
+		}
+	}
+
+	return m, nil
 }
@@ -3,9 +3,14 @@ package url_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/amberdance/url-shortener/internal/app/command"
 	urlusecase "github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/hashgen"
 	"github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
 	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
 	"github.com/stretchr/testify/assert"
@@ -13,8 +18,8 @@ import (
 
 func TestGetByHashUseCase_Run_Success(t *testing.T) {
 	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
-	create := urlusecase.NewCreateURLUseCase(repo)
-	get := urlusecase.NewGetByHashUseCase(repo)
+	create := urlusecase.NewCreateURLUseCase(repo, shared.SystemClock{}, model.AliasPolicy{}, hashgen.NewRandomGenerator(8))
+	get := urlusecase.NewGetByHashUseCase(repo, shared.SystemClock{})
 	cmd := command.CreateURLEntryCommand{OriginalURL: "https://hard2code.ru"}
 
 	m, err := create.Run(context.Background(), cmd)
@@ -27,8 +32,55 @@ func TestGetByHashUseCase_Run_Success(t *testing.T) {
 
 func TestGetByHashUseCase_Run_NotFound(t *testing.T) {
 	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
-	get := urlusecase.NewGetByHashUseCase(repo)
+	get := urlusecase.NewGetByHashUseCase(repo, shared.SystemClock{})
 
 	_, err := get.Run(context.Background(), command.GetURLByHashCommand{Hash: "none"})
-	assert.Error(t, err)
+	assert.ErrorAs(t, err, new(errs.NotFoundError))
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestGetByHashUseCase_Run_Expired(t *testing.T) {
+	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	now := time.Now()
+	expiresAt := now.Add(time.Hour)
+
+	create := urlusecase.NewCreateURLUseCase(repo, fixedClock(now), model.AliasPolicy{}, hashgen.NewRandomGenerator(8))
+	m, err := create.Run(context.Background(), command.CreateURLEntryCommand{OriginalURL: "https://hard2code.ru", ExpiresAt: &expiresAt})
+	assert.NoError(t, err)
+
+	_, err = urlusecase.NewGetByHashUseCase(repo, fixedClock(now)).Run(context.Background(), command.GetURLByHashCommand{Hash: m.Hash})
+	assert.NoError(t, err)
+
+	_, err = urlusecase.NewGetByHashUseCase(repo, fixedClock(expiresAt)).Run(context.Background(), command.GetURLByHashCommand{Hash: m.Hash})
+	assert.ErrorAs(t, err, new(errs.GoneError))
+}
+
+func TestGetByHashUseCase_Run_MaxClicks(t *testing.T) {
+	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	maxClicks := 2
+
+	create := urlusecase.NewCreateURLUseCase(repo, shared.SystemClock{}, model.AliasPolicy{}, hashgen.NewRandomGenerator(8))
+	m, err := create.Run(context.Background(), command.CreateURLEntryCommand{OriginalURL: "https://hard2code.ru", MaxClicks: &maxClicks})
+	assert.NoError(t, err)
+
+	get := urlusecase.NewGetByHashUseCase(repo, shared.SystemClock{})
+	for range maxClicks {
+		_, err = get.Run(context.Background(), command.GetURLByHashCommand{Hash: m.Hash})
+		assert.NoError(t, err)
+	}
+
+	_, err = get.Run(context.Background(), command.GetURLByHashCommand{Hash: m.Hash})
+	assert.ErrorAs(t, err, new(errs.GoneError))
+}
+
+func TestCreateUseCase_Run_PastExpiration(t *testing.T) {
+	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	past := time.Now().Add(-time.Minute)
+
+	_, err := urlusecase.NewCreateURLUseCase(repo, shared.SystemClock{}, model.AliasPolicy{}, hashgen.NewRandomGenerator(8)).
+		Run(context.Background(), command.CreateURLEntryCommand{OriginalURL: "https://hard2code.ru", ExpiresAt: &past})
+	assert.ErrorAs(t, err, new(errs.ValidationError))
 }
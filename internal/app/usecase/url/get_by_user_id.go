@@ -0,0 +1,25 @@
+package url
+
+import (
+	"context"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+)
+
+type GetByUserIDUseCase struct {
+	repository repository.URLRepository
+}
+
+func NewGetByUserIDUseCase(r repository.URLRepository) GetByUserIDUseCase {
+	return GetByUserIDUseCase{repository: r}
+}
+
+func (uc GetByUserIDUseCase) Run(ctx context.Context, cmd command.GetURLsByUserIDCommand) (_ []*model.URL, err error) {
+	ctx, span := startSpan(ctx, "GetURLsByUserID")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	return uc.repository.FindByUserID(ctx, cmd.UserID)
+}
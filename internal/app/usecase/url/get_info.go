@@ -0,0 +1,61 @@
+package url
+
+import (
+	"context"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/google/uuid"
+)
+
+type GetURLInfoUseCase struct {
+	urls   repository.URLRepository
+	clicks repository.ClickRepository
+	clock  shared.Clock
+}
+
+func NewGetURLInfoUseCase(u repository.URLRepository, c repository.ClickRepository, clock shared.Clock) GetURLInfoUseCase {
+	return GetURLInfoUseCase{urls: u, clicks: c, clock: clock}
+}
+
+// Run возвращает ссылку со статистикой, не засчитывая переход. В отличие
+// от GetByHashUseCase, удалённые и истёкшие ссылки тоже возвращаются —
+// с соответствующим Status.
+func (uc GetURLInfoUseCase) Run(ctx context.Context, cmd command.GetURLInfoCommand) (_ *model.URLInfo, err error) {
+	ctx, span := startSpan(ctx, "GetURLInfo")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	var m *model.URL
+	switch {
+	case cmd.Hash != "":
+		m, err = uc.urls.FindByHash(ctx, cmd.Hash)
+	case cmd.OriginalURL != "":
+		m, err = uc.urls.FindByOriginalURL(ctx, cmd.OriginalURL)
+	default:
+		return nil, errs.ValidationError("hash or original_url is required")
+	}
+	if err != nil {
+		return nil, lookupError(err)
+	}
+	if m == nil {
+		return nil, errs.NotFoundError("url not found")
+	}
+	if cmd.UserID != nil && (m.UserID == nil || *m.UserID != *cmd.UserID) {
+		return nil, errs.NotFoundError("url not found")
+	}
+
+	counts, err := uc.clicks.CountByURL(ctx, []uuid.UUID{m.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.URLInfo{
+		URL:         m,
+		TotalClicks: counts[m.ID],
+		Status:      m.Status(uc.clock.Now()),
+	}, nil
+}
@@ -0,0 +1,54 @@
+package url_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	urlusecase "github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository/click"
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// brokenRepository отвечает на поиск по коду сбоем хранилища.
+type brokenRepository struct {
+	repository.URLRepository
+}
+
+var errStorageDown = errors.New("connection refused")
+
+func (brokenRepository) FindByHash(context.Context, string) (*model.URL, error) {
+	return nil, errStorageDown
+}
+
+func TestGetURLInfoUseCase_Run_NotFound(t *testing.T) {
+	st := storage.NewInMemoryStorage()
+	get := urlusecase.NewGetURLInfoUseCase(url.NewInMemoryURLRepository(st), click.NewInMemoryClickRepository(st), shared.SystemClock{})
+
+	_, err := get.Run(context.Background(), command.GetURLInfoCommand{Hash: "none"})
+	assert.ErrorAs(t, err, new(errs.NotFoundError))
+}
+
+func TestLookup_StorageFailureIsNotNotFound(t *testing.T) {
+	st := storage.NewInMemoryStorage()
+	repo := brokenRepository{url.NewInMemoryURLRepository(st)}
+
+	_, err := urlusecase.NewGetURLInfoUseCase(repo, click.NewInMemoryClickRepository(st), shared.SystemClock{}).
+		Run(context.Background(), command.GetURLInfoCommand{Hash: "abc"})
+	assert.ErrorIs(t, err, errStorageDown)
+	assert.NotErrorAs(t, err, new(errs.NotFoundError))
+
+	noIndex := true
+	_, err = urlusecase.NewUpdateURLUseCase(repo, shared.SystemClock{}).Run(context.Background(),
+		command.UpdateURLCommand{UserID: uuid.New(), Hash: "abc", Change: model.URLChange{NoIndex: &noIndex}})
+	assert.ErrorIs(t, err, errStorageDown)
+	assert.NotErrorAs(t, err, new(errs.NotFoundError))
+}
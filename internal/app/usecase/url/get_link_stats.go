@@ -0,0 +1,52 @@
+package url
+
+import (
+	"context"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+)
+
+const (
+	statsDays  = 30
+	statsHours = 24
+)
+
+type GetLinkStatsUseCase struct {
+	urls   repository.URLRepository
+	clicks repository.ClickRepository
+	clock  shared.Clock
+}
+
+func NewGetLinkStatsUseCase(u repository.URLRepository, c repository.ClickRepository, clock shared.Clock) GetLinkStatsUseCase {
+	return GetLinkStatsUseCase{urls: u, clicks: c, clock: clock}
+}
+
+// Run возвращает статистику переходов за всё время и временные ряды за
+// последние statsDays дней и statsHours часов. Чужие ссылки не отличаются
+// от несуществующих.
+func (uc GetLinkStatsUseCase) Run(ctx context.Context, cmd command.GetLinkStatsCommand) (_ *model.ClickStats, err error) {
+	ctx, span := startSpan(ctx, "GetLinkStats")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	m, err := uc.urls.FindByHash(ctx, cmd.Hash)
+	if err != nil {
+		return nil, lookupError(err)
+	}
+	if m == nil || m.UserID == nil || *m.UserID != cmd.UserID {
+		return nil, errs.NotFoundError("url not found")
+	}
+
+	now := uc.clock.Now().UTC()
+
+	return uc.clicks.Stats(ctx, model.ClickStatsQuery{
+		URLID:      m.ID,
+		DailyFrom:  now.Truncate(24*time.Hour).AddDate(0, 0, -(statsDays - 1)),
+		HourlyFrom: now.Truncate(time.Hour).Add(-(statsHours - 1) * time.Hour),
+	})
+}
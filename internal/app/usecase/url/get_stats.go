@@ -0,0 +1,34 @@
+package url
+
+import (
+	"context"
+
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+)
+
+type GetStatsUseCase struct {
+	repository repository.URLRepository
+}
+
+func NewGetStatsUseCase(r repository.URLRepository) GetStatsUseCase {
+	return GetStatsUseCase{repository: r}
+}
+
+func (uc GetStatsUseCase) Run(ctx context.Context) (_ *model.Stats, err error) {
+	ctx, span := startSpan(ctx, "GetStats")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	urls, err := uc.repository.CountURLs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := uc.repository.CountUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Stats{URLs: urls, Users: users}, nil
+}
@@ -0,0 +1,36 @@
+package url
+
+import (
+	"context"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+)
+
+type ClickQueue interface {
+	Record(click *model.Click) error
+}
+
+type RecordClickUseCase struct {
+	queue ClickQueue
+	clock shared.Clock
+}
+
+func NewRecordClickUseCase(q ClickQueue, c shared.Clock) RecordClickUseCase {
+	return RecordClickUseCase{queue: q, clock: c}
+}
+
+func (uc RecordClickUseCase) Run(ctx context.Context, cmd command.RecordClickCommand) (err error) {
+	_, span := startSpan(ctx, "RecordClick")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	return uc.queue.Record(&model.Click{
+		URLID:      cmd.URLID,
+		OccurredAt: uc.clock.Now(),
+		Referrer:   cmd.Referrer,
+		UserAgent:  cmd.UserAgent,
+		IP:         model.AnonymizeIP(cmd.IP),
+	})
+}
@@ -0,0 +1,73 @@
+package url
+
+import (
+	"context"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+)
+
+type GetURLRevisionsUseCase struct {
+	repository repository.URLRepository
+}
+
+func NewGetURLRevisionsUseCase(r repository.URLRepository) GetURLRevisionsUseCase {
+	return GetURLRevisionsUseCase{repository: r}
+}
+
+// Run возвращает прежние состояния ссылки, новые первыми.
+func (uc GetURLRevisionsUseCase) Run(ctx context.Context, cmd command.GetURLRevisionsCommand) (_ []*model.URLRevision, err error) {
+	ctx, span := startSpan(ctx, "GetURLRevisions")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	m, err := findOwnedURL(ctx, uc.repository, cmd.UserID, cmd.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.repository.FindRevisions(ctx, m.ID)
+}
+
+type RollbackURLUseCase struct {
+	repository repository.URLRepository
+	clock      shared.Clock
+}
+
+func NewRollbackURLUseCase(r repository.URLRepository, c shared.Clock) RollbackURLUseCase {
+	return RollbackURLUseCase{repository: r, clock: c}
+}
+
+// Run возвращает ссылку к состоянию ревизии. Откат — такое же изменение,
+// как и остальные: текущее состояние тоже сохраняется ревизией.
+func (uc RollbackURLUseCase) Run(ctx context.Context, cmd command.RollbackURLCommand) (_ *model.URL, err error) {
+	ctx, span := startSpan(ctx, "RollbackURL")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	m, err := findOwnedURL(ctx, uc.repository, cmd.UserID, cmd.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	revs, err := uc.repository.FindRevisions(ctx, m.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rev := range revs {
+		if rev.ID != cmd.RevisionID {
+			continue
+		}
+
+		restored, current := m.Restore(rev, uc.clock.Now())
+		if err = uc.repository.Update(ctx, restored, current); err != nil {
+			return nil, err
+		}
+		return restored, nil
+	}
+
+	return nil, errs.NotFoundError("revision not found")
+}
@@ -0,0 +1,19 @@
+package url
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/amberdance/url-shortener/internal/app/usecase/url"
+
+var batchSizeKey = attribute.Key("shortener.batch_size")
+
+// startSpan открывает дочерний span через провайдер, которым создан span
+// в ctx. Без входящей трассы span не записывается.
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	tracer := trace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName)
+	return tracer.Start(ctx, "usecase."+name)
+}
@@ -0,0 +1,74 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/google/uuid"
+)
+
+type UpdateURLUseCase struct {
+	repository repository.URLRepository
+	clock      shared.Clock
+}
+
+func NewUpdateURLUseCase(r repository.URLRepository, c shared.Clock) UpdateURLUseCase {
+	return UpdateURLUseCase{repository: r, clock: c}
+}
+
+// Run меняет адрес и ограничения ссылки, не меняя её код, и сохраняет
+// прежнее состояние ревизией. Если новый адрес уже сокращён другой
+// ссылкой, возвращает DuplicateEntryError.
+func (uc UpdateURLUseCase) Run(ctx context.Context, cmd command.UpdateURLCommand) (_ *model.URL, err error) {
+	ctx, span := startSpan(ctx, "UpdateURL")
+	defer func() { telemetry.EndSpan(span, err) }()
+
+	if cmd.Change.IsEmpty() {
+		return nil, errs.ValidationError("nothing to update")
+	}
+
+	m, err := findOwnedURL(ctx, uc.repository, cmd.UserID, cmd.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, rev, err := m.Revise(cmd.Change, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = uc.repository.Update(ctx, updated, rev); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// findOwnedURL ищет неудалённую ссылку пользователя userID. Чужие ссылки
+// не отличаются от несуществующих.
+func findOwnedURL(ctx context.Context, r repository.URLRepository, userID uuid.UUID, hash string) (*model.URL, error) {
+	m, err := r.FindByHash(ctx, hash)
+	if err != nil {
+		return nil, lookupError(err)
+	}
+	if m == nil || m.IsDeleted || m.UserID == nil || *m.UserID != userID {
+		return nil, errs.NotFoundError("url not found")
+	}
+	return m, nil
+}
+
+// lookupError пропускает errs.NotFoundError как есть, а сбой хранилища
+// дополняет контекстом, чтобы он не превратился в 404.
+func lookupError(err error) error {
+	var notFound errs.NotFoundError
+	if errors.As(err, &notFound) {
+		return notFound
+	}
+	return fmt.Errorf("failed to find url: %w", err)
+}
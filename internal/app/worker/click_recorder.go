@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+)
+
+const (
+	clickQueueSize     = 4096
+	clickBatchSize     = 500
+	clickFlushInterval = time.Second
+	clickFlushTimeout  = 10 * time.Second
+)
+
+var (
+	ErrRecorderStopped = errors.New("click recorder is stopped")
+	ErrRecorderFull    = errors.New("click recorder queue is full")
+)
+
+// ClickRecorder копит переходы по ссылкам и пишет их в репозиторий пачками,
+// чтобы запись статистики не задерживала редирект.
+type ClickRecorder struct {
+	repo   repository.ClickRepository
+	logger shared.Logger
+
+	mu     sync.RWMutex
+	queue  chan *model.Click
+	done   chan struct{}
+	closed bool
+}
+
+func NewClickRecorder(r repository.ClickRepository, l shared.Logger) *ClickRecorder {
+	return &ClickRecorder{
+		repo:   r,
+		logger: l,
+		queue:  make(chan *model.Click, clickQueueSize),
+		done:   make(chan struct{}),
+	}
+}
+
+func (c *ClickRecorder) Start() {
+	go c.run()
+}
+
+// Record ставит переход в очередь и никогда не блокируется: при
+// переполненной очереди переход отбрасывается с ErrRecorderFull.
+func (c *ClickRecorder) Record(click *model.Click) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closed {
+		return ErrRecorderStopped
+	}
+
+	select {
+	case c.queue <- click:
+		return nil
+	default:
+		return ErrRecorderFull
+	}
+}
+
+// Stop перестаёт принимать переходы и ждёт, пока очередь будет записана.
+func (c *ClickRecorder) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.queue)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *ClickRecorder) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(clickFlushInterval)
+	defer ticker.Stop()
+
+	pending := make([]*model.Click, 0, clickBatchSize)
+
+	for {
+		select {
+		case click, ok := <-c.queue:
+			if !ok {
+				c.flush(pending)
+				return
+			}
+			pending = append(pending, click)
+			if len(pending) >= clickBatchSize {
+				c.flush(pending)
+				pending = make([]*model.Click, 0, clickBatchSize)
+			}
+		case <-ticker.C:
+			if len(pending) > 0 {
+				c.flush(pending)
+				pending = make([]*model.Click, 0, clickBatchSize)
+			}
+		}
+	}
+}
+
+func (c *ClickRecorder) flush(pending []*model.Click) {
+	if len(pending) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clickFlushTimeout)
+	defer cancel()
+
+	if err := c.repo.SaveBatch(ctx, pending); err != nil {
+		c.logger.Error("failed to save clicks", "count", len(pending), "error", err)
+	}
+}
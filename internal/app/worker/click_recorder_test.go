@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository/click"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickRecorder_DrainsQueueOnStop(t *testing.T) {
+	ctx := context.Background()
+	repo := click.NewInMemoryClickRepository(storage.NewInMemoryStorage())
+	urlID := uuid.New()
+
+	r := NewClickRecorder(repo, nopLogger{})
+	r.Start()
+
+	for _, ip := range []string{"192.168.1.10", "192.168.1.20", "2001:db8:1:2::1"} {
+		require.NoError(t, r.Record(&model.Click{URLID: urlID, OccurredAt: time.Now(), IP: model.AnonymizeIP(ip)}))
+	}
+	require.NoError(t, r.Stop(ctx))
+
+	stats, err := repo.Stats(ctx, model.ClickStatsQuery{URLID: urlID})
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Total)
+	assert.Equal(t, 2, stats.UniqueVisitors)
+
+	assert.ErrorIs(t, r.Record(&model.Click{URLID: urlID}), ErrRecorderStopped)
+}
+
+func TestClickRecorder_DropsWhenFull(t *testing.T) {
+	r := NewClickRecorder(click.NewInMemoryClickRepository(storage.NewInMemoryStorage()), nopLogger{})
+
+	for range clickQueueSize {
+		require.NoError(t, r.Record(&model.Click{}))
+	}
+	assert.ErrorIs(t, r.Record(&model.Click{}), ErrRecorderFull)
+}
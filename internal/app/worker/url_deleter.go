@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/google/uuid"
+)
+
+const (
+	deleteQueueSize     = 1024
+	deleteBatchSize     = 500
+	deleteFlushInterval = time.Second
+	deleteFlushTimeout  = 10 * time.Second
+	// deleteAttempts и deleteRetryBackoff ограничивают повторы сброса,
+	// если хранилище временно недоступно; пауза удваивается с каждой
+	// попыткой.
+	deleteAttempts     = 4
+	deleteRetryBackoff = 50 * time.Millisecond
+)
+
+var ErrDeleterStopped = errors.New("url deleter is stopped")
+
+// URLDeleter копит запросы на удаление ссылок и сбрасывает их в репозиторий
+// пачками: все хэши за окно сброса уходят одним запросом.
+type URLDeleter struct {
+	repo   repository.URLRepository
+	logger shared.Logger
+
+	mu     sync.RWMutex
+	queue  chan command.DeleteURLsCommand
+	done   chan struct{}
+	closed bool
+}
+
+func NewURLDeleter(r repository.URLRepository, l shared.Logger) *URLDeleter {
+	return &URLDeleter{
+		repo:   r,
+		logger: l,
+		queue:  make(chan command.DeleteURLsCommand, deleteQueueSize),
+		done:   make(chan struct{}),
+	}
+}
+
+func (d *URLDeleter) Start() {
+	go d.run()
+}
+
+func (d *URLDeleter) Enqueue(ctx context.Context, cmd command.DeleteURLsCommand) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		return ErrDeleterStopped
+	}
+
+	select {
+	case d.queue <- cmd:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop перестаёт принимать новые запросы и ждёт, пока очередь будет сброшена
+// в репозиторий.
+func (d *URLDeleter) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *URLDeleter) run() {
+	defer close(d.done)
+
+	ticker := time.NewTicker(deleteFlushInterval)
+	defer ticker.Stop()
+
+	pending := make(map[uuid.UUID][]string)
+	size := 0
+
+	for {
+		select {
+		case cmd, ok := <-d.queue:
+			if !ok {
+				d.flush(pending)
+				return
+			}
+			pending[cmd.UserID] = append(pending[cmd.UserID], cmd.Hashes...)
+			size += len(cmd.Hashes)
+			if size >= deleteBatchSize {
+				d.flush(pending)
+				pending, size = make(map[uuid.UUID][]string), 0
+			}
+		case <-ticker.C:
+			if size > 0 {
+				d.flush(pending)
+				pending, size = make(map[uuid.UUID][]string), 0
+			}
+		}
+	}
+}
+
+func (d *URLDeleter) flush(pending map[uuid.UUID][]string) {
+	if len(pending) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), deleteFlushTimeout)
+	defer cancel()
+
+	backoff := deleteRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := d.repo.DeleteBatch(ctx, pending)
+		if err == nil {
+			return
+		}
+		if attempt == deleteAttempts {
+			d.logger.Error("failed to delete urls", "users", len(pending), "attempts", attempt, "error", err)
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			d.logger.Error("failed to delete urls", "users", len(pending), "attempts", attempt, "error", err)
+			return
+		}
+	}
+}
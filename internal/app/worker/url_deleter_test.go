@@ -0,0 +1,99 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(_ string, _ ...any)                           {}
+func (nopLogger) Info(_ string, _ ...any)                            {}
+func (nopLogger) Error(_ string, _ ...any)                           {}
+func (nopLogger) DebugContext(_ context.Context, _ string, _ ...any) {}
+func (nopLogger) InfoContext(_ context.Context, _ string, _ ...any)  {}
+func (nopLogger) ErrorContext(_ context.Context, _ string, _ ...any) {}
+func (nopLogger) With(_ ...any) shared.Logger                        { return nopLogger{} }
+func (nopLogger) Close() error                                       { return nil }
+
+func TestURLDeleter_DrainsQueueOnStop(t *testing.T) {
+	ctx := context.Background()
+	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	userID := uuid.New()
+
+	m, err := model.NewURL("https://hard2code.ru", "abc", nil, &userID, time.Now())
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Create(ctx, m))
+
+	d := NewURLDeleter(repo, nopLogger{})
+	d.Start()
+
+	assert.NoError(t, d.Enqueue(ctx, command.DeleteURLsCommand{UserID: userID, Hashes: []string{"abc"}}))
+	assert.NoError(t, d.Stop(ctx))
+
+	found, err := repo.FindByHash(ctx, "abc")
+	assert.NoError(t, err)
+	assert.True(t, found.IsDeleted)
+	assert.NotNil(t, found.DeletedAt)
+
+	assert.ErrorIs(t, d.Enqueue(ctx, command.DeleteURLsCommand{UserID: userID, Hashes: []string{"abc"}}), ErrDeleterStopped)
+}
+
+// flakyRepository отказывает в первых failures вызовах DeleteBatch и
+// запоминает, сколько было вызовов.
+type flakyRepository struct {
+	repository.URLRepository
+
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (r *flakyRepository) DeleteBatch(ctx context.Context, byUser map[uuid.UUID][]string) error {
+	r.mu.Lock()
+	r.calls++
+	fail := r.calls <= r.failures
+	r.mu.Unlock()
+
+	if fail {
+		return errors.New("connection reset")
+	}
+	return r.URLRepository.DeleteBatch(ctx, byUser)
+}
+
+func TestURLDeleter_RetriesSingleBatch(t *testing.T) {
+	ctx := context.Background()
+	repo := &flakyRepository{URLRepository: url.NewInMemoryURLRepository(storage.NewInMemoryStorage()), failures: 1}
+	alice, bob := uuid.New(), uuid.New()
+
+	for hash, owner := range map[string]uuid.UUID{"a": alice, "b": bob} {
+		m, err := model.NewURL("https://hard2code.ru/"+hash, hash, nil, &owner, time.Now())
+		assert.NoError(t, err)
+		assert.NoError(t, repo.Create(ctx, m))
+	}
+
+	d := NewURLDeleter(repo, nopLogger{})
+	d.Start()
+	assert.NoError(t, d.Enqueue(ctx, command.DeleteURLsCommand{UserID: alice, Hashes: []string{"a"}}))
+	assert.NoError(t, d.Enqueue(ctx, command.DeleteURLsCommand{UserID: bob, Hashes: []string{"b"}}))
+	assert.NoError(t, d.Stop(ctx))
+
+	assert.Equal(t, 2, repo.calls, "оба пользователя уходят одним запросом, повторённым после сбоя")
+	for _, hash := range []string{"a", "b"} {
+		found, err := repo.FindByHash(ctx, hash)
+		assert.NoError(t, err)
+		assert.True(t, found.IsDeleted, hash)
+	}
+}
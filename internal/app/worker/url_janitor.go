@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+)
+
+const janitorPurgeTimeout = 30 * time.Second
+
+// URLJanitor периодически убирает из репозитория ссылки с истёкшим сроком
+// жизни или исчерпанным лимитом переходов.
+type URLJanitor struct {
+	repo     repository.URLRepository
+	clock    shared.Clock
+	logger   shared.Logger
+	interval time.Duration
+
+	once sync.Once
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewURLJanitor(r repository.URLRepository, c shared.Clock, l shared.Logger, interval time.Duration) *URLJanitor {
+	return &URLJanitor{
+		repo:     r,
+		clock:    c,
+		logger:   l,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (j *URLJanitor) Start() {
+	go j.run()
+}
+
+// Stop останавливает janitor и ждёт завершения текущей очистки.
+func (j *URLJanitor) Stop(ctx context.Context) error {
+	j.once.Do(func() { close(j.stop) })
+
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Purge удаляет просроченные ссылки один раз.
+func (j *URLJanitor) Purge(ctx context.Context) (int, error) {
+	return j.repo.PurgeExpired(ctx, j.clock.Now())
+}
+
+func (j *URLJanitor) run() {
+	defer close(j.done)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), janitorPurgeTimeout)
+			n, err := j.Purge(ctx)
+			cancel()
+
+			if err != nil {
+				j.logger.Error("failed to purge expired urls", "error", err)
+				continue
+			}
+			if n > 0 {
+				j.logger.Info("purged expired urls", "count", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLJanitor_PurgesExpired(t *testing.T) {
+	ctx := context.Background()
+	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	now := time.Now()
+
+	expired, err := model.NewURL("https://hard2code.ru", "old", nil, nil, now.Add(-time.Hour))
+	require.NoError(t, err)
+	expiresAt := now.Add(-time.Minute)
+	expired.ExpiresAt = &expiresAt
+	require.NoError(t, repo.Create(ctx, expired))
+
+	exhausted, err := model.NewURL("https://example.com", "used", nil, nil, now)
+	require.NoError(t, err)
+	maxClicks := 1
+	exhausted.MaxClicks = &maxClicks
+	exhausted.Clicks = 1
+	require.NoError(t, repo.Create(ctx, exhausted))
+
+	alive, err := model.NewURL("https://example.org", "alive", nil, nil, now)
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, alive))
+
+	j := NewURLJanitor(repo, shared.SystemClock{}, nopLogger{}, time.Hour)
+	n, err := j.Purge(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	_, err = repo.FindByHash(ctx, "old")
+	assert.Error(t, err)
+	_, err = repo.FindByHash(ctx, "alive")
+	assert.NoError(t, err)
+}
+
+func TestURLJanitor_Stop(t *testing.T) {
+	repo := url.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	j := NewURLJanitor(repo, shared.SystemClock{}, nopLogger{}, time.Millisecond)
+	j.Start()
+
+	time.Sleep(5 * time.Millisecond)
+	assert.NoError(t, j.Stop(context.Background()))
+	assert.NoError(t, j.Stop(context.Background()))
+}
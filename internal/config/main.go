@@ -1,72 +1,519 @@
+// Package config собирает настройки сервиса из нескольких источников.
+//
+// Приоритет источников (от высшего к низшему):
+//
+//  1. флаги командной строки;
+//  2. переменные окружения (включая .env);
+//  3. файл конфигурации, указанный флагом -c или переменной CONFIG
+//     (JSON, YAML или TOML — формат определяется по расширению);
+//  4. значения по умолчанию.
+//
+// Каждое поле Config должно иметь теги env, json, yaml и toml, чтобы его
+// можно было задать из любого источника.
 package config
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
-	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Address         string `env:"SERVER_ADDRESS" env-default:"0.0.0.0:8080"`
-	BaseURL         string `env:"BASE_URL" env-default:""`
-	LogLevel        string `env:"LOG_LEVEL" env-default:"info"`
-	FileStoragePath string `env:"FILE_STORAGE_PATH"`
-	DatabaseDSN     string `env:"DATABASE_DSN"`
+	Address                     string        `env:"SERVER_ADDRESS" env-default:"0.0.0.0:8080" json:"server_address" yaml:"server_address" toml:"server_address"`
+	BaseURL                     string        `env:"BASE_URL" env-default:"" json:"base_url" yaml:"base_url" toml:"base_url"`
+	LogLevel                    string        `env:"LOG_LEVEL" env-default:"info" json:"log_level" yaml:"log_level" toml:"log_level"`
+	LogFormat                   string        `env:"LOG_FORMAT" env-default:"text" json:"log_format" yaml:"log_format" toml:"log_format"`
+	LogFile                     string        `env:"LOG_FILE" env-default:"./logs/app.log" json:"log_file" yaml:"log_file" toml:"log_file"`
+	LogMaxSizeMB                int           `env:"LOG_MAX_SIZE_MB" env-default:"100" json:"log_max_size_mb" yaml:"log_max_size_mb" toml:"log_max_size_mb"`
+	LogRotateEvery              time.Duration `env:"LOG_ROTATE_EVERY" env-default:"24h" json:"log_rotate_every" yaml:"log_rotate_every" toml:"log_rotate_every"`
+	LogMaxBackups               int           `env:"LOG_MAX_BACKUPS" env-default:"7" json:"log_max_backups" yaml:"log_max_backups" toml:"log_max_backups"`
+	FileStoragePath             string        `env:"FILE_STORAGE_PATH" json:"file_storage_path" yaml:"file_storage_path" toml:"file_storage_path"`
+	DatabaseDSN                 string        `env:"DATABASE_DSN" json:"database_dsn" yaml:"database_dsn" toml:"database_dsn"`
+	SecretKey                   string        `env:"SECRET_KEY" json:"secret_key" yaml:"secret_key" toml:"secret_key"`
+	TrustedSubnet               string        `env:"TRUSTED_SUBNET" json:"trusted_subnet" yaml:"trusted_subnet" toml:"trusted_subnet"`
+	GRPCAddress                 string        `env:"GRPC_ADDRESS" json:"grpc_address" yaml:"grpc_address" toml:"grpc_address"`
+	EnableHTTPS                 bool          `env:"ENABLE_HTTPS" json:"enable_https" yaml:"enable_https" toml:"enable_https"`
+	TLSCertFile                 string        `env:"TLS_CERT_FILE" json:"tls_cert_file" yaml:"tls_cert_file" toml:"tls_cert_file"`
+	TLSKeyFile                  string        `env:"TLS_KEY_FILE" json:"tls_key_file" yaml:"tls_key_file" toml:"tls_key_file"`
+	HTTPRedirect                string        `env:"HTTP_REDIRECT_ADDRESS" json:"http_redirect_address" yaml:"http_redirect_address" toml:"http_redirect_address"`
+	AliasCharset                string        `env:"ALIAS_CHARSET" env-default:"abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_" json:"alias_charset" yaml:"alias_charset" toml:"alias_charset"`
+	AliasMinLength              int           `env:"ALIAS_MIN_LENGTH" env-default:"3" json:"alias_min_length" yaml:"alias_min_length" toml:"alias_min_length"`
+	AliasMaxLength              int           `env:"ALIAS_MAX_LENGTH" env-default:"64" json:"alias_max_length" yaml:"alias_max_length" toml:"alias_max_length"`
+	ReservedAliases             []string      `env:"RESERVED_ALIASES" env-default:"api,health,ping,metrics,static" env-separator:"," json:"reserved_aliases" yaml:"reserved_aliases" toml:"reserved_aliases"`
+	FileStorageSync             string        `env:"FILE_STORAGE_SYNC" env-default:"interval" json:"file_storage_sync" yaml:"file_storage_sync" toml:"file_storage_sync"`
+	FileStorageCompactThreshold int           `env:"FILE_STORAGE_COMPACT_THRESHOLD" env-default:"10000" json:"file_storage_compact_threshold" yaml:"file_storage_compact_threshold" toml:"file_storage_compact_threshold"`
+	FileStorageCompactInterval  time.Duration `env:"FILE_STORAGE_COMPACT_INTERVAL" env-default:"10m" json:"file_storage_compact_interval" yaml:"file_storage_compact_interval" toml:"file_storage_compact_interval"`
+	// JanitorInterval задаёт период удаления просроченных ссылок.
+	JanitorInterval time.Duration `env:"JANITOR_INTERVAL" env-default:"1m" json:"janitor_interval" yaml:"janitor_interval" toml:"janitor_interval"`
+	// HashGenerator выбирает способ генерации коротких кодов: random,
+	// sequence, hashids или content.
+	HashGenerator string `env:"HASH_GENERATOR" env-default:"random" json:"hash_generator" yaml:"hash_generator" toml:"hash_generator"`
+	HashLength    int    `env:"HASH_LENGTH" env-default:"8" json:"hash_length" yaml:"hash_length" toml:"hash_length"`
+	HashSalt      string `env:"HASH_SALT" json:"hash_salt" yaml:"hash_salt" toml:"hash_salt"`
+	// ImportMaxBodyMB ограничивает распакованный размер тела
+	// POST /api/shorten/import.
+	ImportMaxBodyMB int `env:"IMPORT_MAX_BODY_MB" env-default:"1024" json:"import_max_body_mb" yaml:"import_max_body_mb" toml:"import_max_body_mb"`
+	// ImportChunkSize — сколько записей импорта сохраняется за один раз.
+	ImportChunkSize int `env:"IMPORT_CHUNK_SIZE" env-default:"1000" json:"import_chunk_size" yaml:"import_chunk_size" toml:"import_chunk_size"`
+	// URLCacheSize — сколько ссылок кэшируется для редиректов при хранении
+	// в PostgreSQL; 0 отключает кэш.
+	URLCacheSize        int           `env:"URL_CACHE_SIZE" env-default:"10000" json:"url_cache_size" yaml:"url_cache_size" toml:"url_cache_size"`
+	URLCacheTTL         time.Duration `env:"URL_CACHE_TTL" env-default:"5m" json:"url_cache_ttl" yaml:"url_cache_ttl" toml:"url_cache_ttl"`
+	URLCacheNegativeTTL time.Duration `env:"URL_CACHE_NEGATIVE_TTL" env-default:"30s" json:"url_cache_negative_ttl" yaml:"url_cache_negative_ttl" toml:"url_cache_negative_ttl"`
+	// RedirectCode — код перенаправления для ссылок, у которых он не задан:
+	// 301, 302, 307 или 308.
+	RedirectCode int `env:"REDIRECT_CODE" env-default:"307" json:"redirect_code" yaml:"redirect_code" toml:"redirect_code"`
+	// RedirectCacheMaxAge — сколько клиенты могут помнить постоянное
+	// перенаправление (301, 308). Изменение и удаление ссылки доходят до
+	// клиента только по истечении этого срока, поэтому он по умолчанию мал.
+	RedirectCacheMaxAge time.Duration `env:"REDIRECT_CACHE_MAX_AGE" env-default:"5m" json:"redirect_cache_max_age" yaml:"redirect_cache_max_age" toml:"redirect_cache_max_age"`
+	// RedirectNoIndex добавляет X-Robots-Tag: noindex ко всем перенаправлениям.
+	RedirectNoIndex bool `env:"REDIRECT_NOINDEX" json:"redirect_noindex" yaml:"redirect_noindex" toml:"redirect_noindex"`
+	// TracingExporter выбирает, куда отправляются трассы: none, otlp,
+	// stdout или file.
+	TracingExporter string `env:"TRACING_EXPORTER" env-default:"none" json:"tracing_exporter" yaml:"tracing_exporter" toml:"tracing_exporter"`
+	// TracingEndpoint — адрес OTLP/HTTP-коллектора для экспортёра otlp.
+	TracingEndpoint string `env:"TRACING_ENDPOINT" env-default:"localhost:4318" json:"tracing_endpoint" yaml:"tracing_endpoint" toml:"tracing_endpoint"`
+	// TracingFile — файл для экспортёра file.
+	TracingFile string `env:"TRACING_FILE" env-default:"./logs/traces.jsonl" json:"tracing_file" yaml:"tracing_file" toml:"tracing_file"`
+
+	secretKeyGenerated bool
 }
 
-var (
-	cfg  *Config
-	once sync.Once
-)
+// insecureSecretKey — значение SECRET_KEY из старых примеров конфигурации.
+const insecureSecretKey = "change-me"
 
-func GetConfig() *Config {
-	once.Do(func() {
-		cfg = &Config{}
+// Default возвращает конфигурацию из значений по умолчанию, без чтения
+// окружения и файлов.
+func Default() Config {
+	var c Config
 
-		if err := godotenv.Load(); err != nil {
-			log.Println("не найден файл .env, используются переменные окружения")
+	v := reflect.ValueOf(&c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if def, ok := field.Tag.Lookup("env-default"); ok {
+			_ = setField(v.Field(i), field, def)
 		}
-		if err := cleanenv.ReadEnv(cfg); err != nil {
-			log.Fatalln(err)
+	}
+
+	return c
+}
+
+// Load разбирает флаги из args и накладывает на значения по умолчанию файл
+// конфигурации, окружение и флаги, после чего проверяет результат.
+func Load(fs *flag.FlagSet, args []string) (*Config, error) {
+	flags := registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	c := Default()
+
+	path := flags.configPath
+	if path == "" {
+		path = os.Getenv("CONFIG")
+	}
+
+	if path != "" {
+		if err := readFile(path, &c); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
 		}
+	}
+
+	if err := readEnv(&c); err != nil {
+		return nil, err
+	}
 
-		address := flag.String("a", "", "Адрес запуска HTTP-сервера (например, localhost:8080)")
-		baseURL := flag.String("b", "", "Базовый адрес коротких ссылок (например, http://localhost:8080)")
-		dbFilePath := flag.String("f", "", "Путь к файловому хранилищу")
-		dsn := flag.String("d", "", "PostgreSQL DSN")
-		flag.Parse()
+	flags.apply(fs, &c)
 
-		if *address != "" {
-			cfg.Address = *address
+	if err := c.Finalize(); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// readFile накладывает на c поля, заданные в файле path.
+func readFile(path string, c *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return cleanenv.ParseJSON(f, c)
+	case ".yaml", ".yml":
+		return cleanenv.ParseYAML(f, c)
+	case ".toml":
+		return cleanenv.ParseTOML(f, c)
+	default:
+		return fmt.Errorf("unsupported format %q: expected .json, .yaml or .toml", ext)
+	}
+}
+
+// readEnv накладывает на c только заданные переменные окружения.
+// cleanenv.ReadEnv для этого не подходит: он подставляет env-default во
+// все нулевые поля и так затирает нули, явно заданные в файле.
+func readEnv(c *Config) error {
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, ok := field.Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
 		}
+		if err := setField(v.Field(i), field, raw); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", name, raw, err)
+		}
+	}
+	return nil
+}
 
-		if *baseURL != "" {
-			cfg.BaseURL = *baseURL
+func setField(v reflect.Value, field reflect.StructField, raw string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Int64:
+		if field.Type != reflect.TypeOf(time.Duration(0)) {
+			return fmt.Errorf("unsupported field type %s", field.Type)
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
 		}
+		v.SetBool(b)
+	case reflect.Slice:
+		sep := field.Tag.Get("env-separator")
+		if sep == "" {
+			sep = ","
+		}
+		var items []string
+		if raw != "" {
+			items = strings.Split(raw, sep)
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type)
+	}
+	return nil
+}
+
+type flagValues struct {
+	configPath    string
+	address       string
+	baseURL       string
+	dbFilePath    string
+	dsn           string
+	grpcAddress   string
+	trustedSubnet string
+	enableHTTPS   bool
+	certFile      string
+	keyFile       string
+	httpRedirect  string
+}
+
+func registerFlags(fs *flag.FlagSet) *flagValues {
+	f := &flagValues{}
+
+	fs.StringVar(&f.configPath, "c", "", "Путь к файлу конфигурации (JSON, YAML или TOML)")
+	fs.StringVar(&f.configPath, "config", "", "Путь к файлу конфигурации (JSON, YAML или TOML)")
+	fs.StringVar(&f.address, "a", "", "Адрес запуска HTTP-сервера (например, localhost:8080)")
+	fs.StringVar(&f.baseURL, "b", "", "Базовый адрес коротких ссылок (например, http://localhost:8080)")
+	fs.StringVar(&f.dbFilePath, "f", "", "Путь к файловому хранилищу")
+	fs.StringVar(&f.dsn, "d", "", "PostgreSQL DSN")
+	fs.StringVar(&f.grpcAddress, "g", "", "Адрес запуска gRPC-сервера (например, localhost:3200)")
+	fs.StringVar(&f.trustedSubnet, "t", "", "Доверенная подсеть в CIDR-нотации (например, 192.168.0.0/24)")
+	fs.BoolVar(&f.enableHTTPS, "s", false, "Включить HTTPS")
+	fs.StringVar(&f.certFile, "cert", "", "Путь к TLS-сертификату; без него генерируется самоподписанный")
+	fs.StringVar(&f.keyFile, "key", "", "Путь к закрытому ключу TLS-сертификата")
+	fs.StringVar(&f.httpRedirect, "r", "", "Адрес HTTP-сервера, перенаправляющего на HTTPS (например, :80)")
+
+	return f
+}
+
+// apply переносит в конфиг только явно переданные флаги, чтобы значения
+// флагов по умолчанию не затирали окружение и файл.
+func (f *flagValues) apply(fs *flag.FlagSet, c *Config) {
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "a":
+			c.Address = f.address
+		case "b":
+			c.BaseURL = f.baseURL
+		case "f":
+			c.FileStoragePath = f.dbFilePath
+		case "d":
+			c.DatabaseDSN = f.dsn
+		case "g":
+			c.GRPCAddress = f.grpcAddress
+		case "t":
+			c.TrustedSubnet = f.trustedSubnet
+		case "s":
+			c.EnableHTTPS = f.enableHTTPS
+		case "cert":
+			c.TLSCertFile = f.certFile
+		case "key":
+			c.TLSKeyFile = f.keyFile
+		case "r":
+			c.HTTPRedirect = f.httpRedirect
+		}
+	})
+}
 
-		if cfg.BaseURL == "" {
-			cfg.BaseURL = fmt.Sprintf("http://%s", cfg.Address)
+// Finalize вычисляет производные значения (например, BaseURL по адресу
+// сервера), генерирует отсутствующий SecretKey и проверяет конфигурацию.
+func (c *Config) Finalize() error {
+	c.normalize()
+	if c.SecretKey == "" {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return fmt.Errorf("failed to generate SECRET_KEY: %w", err)
 		}
+		c.SecretKey = hex.EncodeToString(key)
+		c.secretKeyGenerated = true
+	}
+	return c.Validate()
+}
+
+// SecretKeyGenerated сообщает, что SecretKey не был задан и сгенерирован
+// при запуске.
+func (c *Config) SecretKeyGenerated() bool { return c.secretKeyGenerated }
 
-		if !strings.HasPrefix(cfg.BaseURL, "http://") && !strings.HasPrefix(cfg.BaseURL, "https://") {
-			cfg.BaseURL = "http://" + cfg.BaseURL
+func (c *Config) normalize() {
+	if c.BaseURL == "" {
+		scheme := "http://"
+		if c.EnableHTTPS {
+			scheme = "https://"
 		}
+		c.BaseURL = scheme + c.Address
+	}
 
-		cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/") + "/"
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/") + "/"
+}
+
+// Validate проверяет значения, которые иначе привели бы к ошибке только
+// в момент использования.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if err := validateAddress(c.Address); err != nil {
+		errs = append(errs, fmt.Errorf("invalid SERVER_ADDRESS %q: %w", c.Address, err))
+	}
 
-		if *dbFilePath != "" {
-			cfg.FileStoragePath = *dbFilePath
+	if err := validateBaseURL(c.BaseURL); err != nil {
+		errs = append(errs, fmt.Errorf("invalid BASE_URL %q: %w", c.BaseURL, err))
+	}
+
+	if c.DatabaseDSN != "" {
+		if err := validateDSN(c.DatabaseDSN); err != nil {
+			errs = append(errs, fmt.Errorf("invalid DATABASE_DSN: %w", err))
 		}
+	}
 
-		if *dsn != "" {
-			cfg.DatabaseDSN = *dsn
+	if c.GRPCAddress != "" {
+		if err := validateAddress(c.GRPCAddress); err != nil {
+			errs = append(errs, fmt.Errorf("invalid GRPC_ADDRESS %q: %w", c.GRPCAddress, err))
 		}
-	})
+	}
+
+	if c.HTTPRedirect != "" {
+		if err := validateAddress(c.HTTPRedirect); err != nil {
+			errs = append(errs, fmt.Errorf("invalid HTTP_REDIRECT_ADDRESS %q: %w", c.HTTPRedirect, err))
+		}
+	}
+
+	switch c.SecretKey {
+	case "":
+		errs = append(errs, errors.New("SECRET_KEY must not be empty"))
+	case insecureSecretKey:
+		errs = append(errs, fmt.Errorf("SECRET_KEY must not be %q: set a random value", insecureSecretKey))
+	}
+
+	if c.TrustedSubnet != "" {
+		if _, err := netip.ParsePrefix(c.TrustedSubnet); err != nil {
+			errs = append(errs, fmt.Errorf("invalid TRUSTED_SUBNET: %w", err))
+		}
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+	}
+
+	if c.AliasCharset == "" {
+		errs = append(errs, errors.New("ALIAS_CHARSET must not be empty"))
+	} else if i := strings.IndexFunc(c.AliasCharset, func(r rune) bool { return !isHashRune(r) }); i >= 0 {
+		errs = append(errs, fmt.Errorf("invalid ALIAS_CHARSET: %q is not allowed in short codes, expected a-z, A-Z, 0-9, _ or -", c.AliasCharset[i:i+1]))
+	}
+
+	if c.AliasMinLength < 1 || c.AliasMaxLength < c.AliasMinLength || c.AliasMaxLength > 255 {
+		errs = append(errs, fmt.Errorf("invalid alias length bounds [%d, %d]: expected 1 <= min <= max <= 255", c.AliasMinLength, c.AliasMaxLength))
+	}
+
+	switch c.FileStorageSync {
+	case "always", "interval", "never":
+	default:
+		errs = append(errs, fmt.Errorf("invalid FILE_STORAGE_SYNC %q: expected always, interval or never", c.FileStorageSync))
+	}
+
+	if c.FileStorageCompactThreshold < 0 || c.FileStorageCompactInterval < 0 {
+		errs = append(errs, errors.New("FILE_STORAGE_COMPACT_THRESHOLD and FILE_STORAGE_COMPACT_INTERVAL must not be negative"))
+	}
+
+	if c.JanitorInterval <= 0 {
+		errs = append(errs, fmt.Errorf("invalid JANITOR_INTERVAL %s: must be positive", c.JanitorInterval))
+	}
+
+	switch c.HashGenerator {
+	case "random", "sequence", "content":
+		if c.HashLength < 4 || c.HashLength > 32 {
+			errs = append(errs, fmt.Errorf("invalid HASH_LENGTH %d: expected 4..32", c.HashLength))
+		}
+	case "hashids":
+		if c.HashLength < 4 || c.HashLength > 10 {
+			errs = append(errs, fmt.Errorf("invalid HASH_LENGTH %d: expected 4..10 for hashids", c.HashLength))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("invalid HASH_GENERATOR %q: expected random, sequence, hashids or content", c.HashGenerator))
+	}
+
+	if c.ImportMaxBodyMB < 1 || c.ImportChunkSize < 1 {
+		errs = append(errs, errors.New("IMPORT_MAX_BODY_MB and IMPORT_CHUNK_SIZE must be positive"))
+	}
+
+	if c.URLCacheSize < 0 {
+		errs = append(errs, fmt.Errorf("invalid URL_CACHE_SIZE %d: must not be negative", c.URLCacheSize))
+	}
+	if c.URLCacheSize > 0 && (c.URLCacheTTL <= 0 || c.URLCacheNegativeTTL <= 0) {
+		errs = append(errs, errors.New("URL_CACHE_TTL and URL_CACHE_NEGATIVE_TTL must be positive"))
+	}
+
+	switch c.RedirectCode {
+	case 301, 302, 307, 308:
+	default:
+		errs = append(errs, fmt.Errorf("invalid REDIRECT_CODE %d: expected 301, 302, 307 or 308", c.RedirectCode))
+	}
+	if c.RedirectCacheMaxAge < 0 {
+		errs = append(errs, fmt.Errorf("invalid REDIRECT_CACHE_MAX_AGE %s: must not be negative", c.RedirectCacheMaxAge))
+	}
+
+	switch c.TracingExporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.TracingEndpoint == "" {
+			errs = append(errs, errors.New("TRACING_ENDPOINT must not be empty for otlp exporter"))
+		}
+	case "file":
+		if c.TracingFile == "" {
+			errs = append(errs, errors.New("TRACING_FILE must not be empty for file exporter"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("invalid TRACING_EXPORTER %q: expected none, otlp, stdout or file", c.TracingExporter))
+	}
+
+	switch c.LogLevel {
+	case "debug", "info", "error":
+	default:
+		errs = append(errs, fmt.Errorf("invalid LOG_LEVEL %q: expected debug, info or error", c.LogLevel))
+	}
+
+	switch c.LogFormat {
+	case "text", "json":
+	default:
+		errs = append(errs, fmt.Errorf("invalid LOG_FORMAT %q: expected text or json", c.LogFormat))
+	}
+
+	if c.LogMaxSizeMB < 0 || c.LogRotateEvery < 0 || c.LogMaxBackups < 0 {
+		errs = append(errs, errors.New("LOG_MAX_SIZE_MB, LOG_ROTATE_EVERY and LOG_MAX_BACKUPS must not be negative"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// isHashRune сообщает, что r допустим в коротком коде: маршрут /{hash}
+// принимает только [a-zA-Z0-9_-].
+func isHashRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-'
+}
+
+func validateAddress(address string) error {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+
+	return nil
+}
+
+func validateBaseURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("scheme must be http or https")
+	}
+
+	if u.Host == "" {
+		return errors.New("host is empty")
+	}
+
+	if u.RawQuery != "" || u.Fragment != "" {
+		return errors.New("query and fragment are not allowed")
+	}
+
+	return nil
+}
+
+func validateDSN(dsn string) error {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return errors.New("malformed url")
+	}
+
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return errors.New("host is empty")
+	}
 
-	return cfg
+	return nil
 }
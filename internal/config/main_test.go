@@ -0,0 +1,135 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func load(args ...string) (*Config, error) {
+	return Load(flag.NewFlagSet("test", flag.ContinueOnError), args)
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	c, err := load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "0.0.0.0:8080", c.Address)
+	assert.Equal(t, "http://0.0.0.0:8080/", c.BaseURL)
+	assert.Equal(t, "info", c.LogLevel)
+	assert.Equal(t, time.Minute, c.JanitorInterval)
+	assert.Equal(t, 307, c.RedirectCode)
+	assert.Equal(t, 5*time.Minute, c.RedirectCacheMaxAge)
+	assert.Len(t, c.SecretKey, 64)
+	assert.True(t, c.SecretKeyGenerated())
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{
+		"server_address": "127.0.0.1:7000",
+		"base_url": "http://file.local",
+		"log_level": "error",
+		"file_storage_path": "/tmp/file.json"
+	}`)
+
+	t.Setenv("CONFIG", path)
+	t.Setenv("BASE_URL", "http://env.local")
+	t.Setenv("LOG_LEVEL", "debug")
+
+	c, err := load("-b", "http://flag.local")
+	require.NoError(t, err)
+
+	assert.Equal(t, "127.0.0.1:7000", c.Address, "file overrides default")
+	assert.Equal(t, "debug", c.LogLevel, "env overrides file")
+	assert.Equal(t, "http://flag.local/", c.BaseURL, "flag overrides env")
+	assert.Equal(t, "/tmp/file.json", c.FileStoragePath)
+}
+
+func TestLoad_YAMLFileFromFlag(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "server_address: 127.0.0.1:7001\nenable_https: true\njanitor_interval: 30s\n")
+
+	c, err := load("-c", path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "127.0.0.1:7001", c.Address)
+	assert.True(t, c.EnableHTTPS)
+	assert.Equal(t, "https://127.0.0.1:7001/", c.BaseURL)
+	assert.Equal(t, 30*time.Second, c.JanitorInterval)
+}
+
+func TestLoad_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"address without port", []string{"-a", "localhost"}},
+		{"address with bad port", []string{"-a", "localhost:http"}},
+		{"base url without scheme", []string{"-b", "localhost:8080"}},
+		{"base url with unsupported scheme", []string{"-b", "ftp://localhost"}},
+		{"dsn with unsupported scheme", []string{"-d", "mysql://user@localhost/db"}},
+		{"dsn without host", []string{"-d", "postgres:///db"}},
+		{"invalid subnet", []string{"-t", "10.0.0.0"}},
+		{"cert without key", []string{"-cert", "cert.pem"}},
+	}
+
+	t.Run("alias charset outside route pattern", func(t *testing.T) {
+		t.Setenv("ALIAS_CHARSET", "abc.~")
+		_, err := load()
+		assert.Error(t, err)
+	})
+
+	t.Run("example secret key", func(t *testing.T) {
+		t.Setenv("SECRET_KEY", "change-me")
+		_, err := load()
+		assert.Error(t, err)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(tt.args...)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoad_MalformedFile(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{"server_address":`)
+
+	_, err := load("-c", path)
+	assert.Error(t, err)
+}
+
+func TestLoad_FileZeroesSurviveDefaults(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "url_cache_size: 0\nlog_file: \"\"\nredirect_cache_max_age: 0s\n")
+
+	c, err := load("-c", path)
+	require.NoError(t, err)
+
+	assert.Zero(t, c.URLCacheSize)
+	assert.Empty(t, c.LogFile)
+	assert.Zero(t, c.RedirectCacheMaxAge)
+	assert.Equal(t, "info", c.LogLevel, "поля, которых нет в файле, берутся по умолчанию")
+
+	t.Setenv("URL_CACHE_SIZE", "50")
+	c, err = load("-c", path)
+	require.NoError(t, err)
+	assert.Equal(t, 50, c.URLCacheSize, "env overrides file")
+}
+
+func TestLoad_InvalidEnvValue(t *testing.T) {
+	t.Setenv("URL_CACHE_SIZE", "many")
+
+	_, err := load()
+	assert.Error(t, err)
+}
@@ -5,3 +5,8 @@ import "context"
 type Pinger interface {
 	Ping(ctx context.Context) error
 }
+
+// NopPinger считает хранилище всегда доступным.
+type NopPinger struct{}
+
+func (NopPinger) Ping(_ context.Context) error { return nil }
@@ -0,0 +1,9 @@
+package errs
+
+type AliasTakenError string
+
+func (e AliasTakenError) Error() string {
+	return string(e)
+}
+
+func (AliasTakenError) ID() string { return "alias_taken" }
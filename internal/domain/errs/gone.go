@@ -0,0 +1,9 @@
+package errs
+
+type GoneError string
+
+func (e GoneError) Error() string {
+	return string(e)
+}
+
+func (GoneError) ID() string { return "gone" }
@@ -0,0 +1,11 @@
+package errs
+
+// HashConflictError означает, что короткий код уже занят. В отличие от
+// DuplicateEntryError, исходный адрес при этом может быть новым.
+type HashConflictError string
+
+func (e HashConflictError) Error() string {
+	return string(e)
+}
+
+func (HashConflictError) ID() string { return "hash_conflict" }
@@ -0,0 +1,13 @@
+package errs
+
+import "errors"
+
+// ID возвращает идентификатор доменной ошибки или internal_error, если err
+// не из этого пакета.
+func ID(err error) string {
+	var identified interface{ ID() string }
+	if errors.As(err, &identified) {
+		return identified.ID()
+	}
+	return "internal_error"
+}
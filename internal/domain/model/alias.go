@@ -0,0 +1,36 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+)
+
+// AliasPolicy описывает, какие пользовательские короткие коды допустимы.
+type AliasPolicy struct {
+	Charset   string
+	MinLength int
+	MaxLength int
+	Reserved  []string
+}
+
+func (p AliasPolicy) Validate(alias string) error {
+	if n := len(alias); n < p.MinLength || n > p.MaxLength {
+		return errs.ValidationError(fmt.Sprintf("alias length must be between %d and %d", p.MinLength, p.MaxLength))
+	}
+
+	for _, r := range alias {
+		if !strings.ContainsRune(p.Charset, r) {
+			return errs.ValidationError(fmt.Sprintf("alias contains forbidden character %q", r))
+		}
+	}
+
+	for _, word := range p.Reserved {
+		if strings.EqualFold(alias, word) {
+			return errs.ValidationError(fmt.Sprintf("alias %q is reserved", alias))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package model
+
+// BatchStatus — итог обработки одной записи пакетного сокращения.
+type BatchStatus string
+
+const (
+	BatchCreated  BatchStatus = "created"
+	BatchExisting BatchStatus = "existing"
+	BatchInvalid  BatchStatus = "invalid"
+)
+
+// BatchResult описывает одну запись пакета. URL заполнен для created и
+// existing, Err — для invalid.
+type BatchResult struct {
+	CorrelationID *string
+	Status        BatchStatus
+	URL           *URL
+	Err           error
+}
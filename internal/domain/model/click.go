@@ -0,0 +1,59 @@
+package model
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Click — один переход по короткой ссылке.
+type Click struct {
+	URLID      uuid.UUID
+	OccurredAt time.Time
+	Referrer   string
+	UserAgent  string
+	// IP хранится обезличенным, см. AnonymizeIP.
+	IP string
+}
+
+// ClickPoint — количество переходов за интервал, начинающийся в Time.
+type ClickPoint struct {
+	Time   time.Time
+	Clicks int
+}
+
+type ClickStats struct {
+	Total          int
+	UniqueVisitors int
+	Daily          []ClickPoint
+	Hourly         []ClickPoint
+}
+
+// ClickStatsQuery задаёт ссылку и начало временных рядов.
+type ClickStatsQuery struct {
+	URLID      uuid.UUID
+	DailyFrom  time.Time
+	HourlyFrom time.Time
+}
+
+// AnonymizeIP обнуляет младшие биты адреса: у IPv4 остаётся /24, у IPv6 — /48.
+// Нераспознанный адрес превращается в пустую строку.
+func AnonymizeIP(raw string) string {
+	addr, err := netip.ParseAddr(raw)
+	if err != nil {
+		return ""
+	}
+
+	addr = addr.Unmap()
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+	return prefix.Addr().String()
+}
@@ -0,0 +1,18 @@
+package model
+
+import "github.com/google/uuid"
+
+// URLPageQuery задаёт страницу выборки ссылок: неудалённые записи с ID
+// больше After в порядке возрастания ID. UserID, если задан, ограничивает
+// выборку одним владельцем.
+type URLPageQuery struct {
+	UserID *uuid.UUID
+	After  uuid.UUID
+	Limit  int
+}
+
+// ExportedURL — ссылка вместе с общим числом переходов по ней.
+type ExportedURL struct {
+	*URL
+	TotalClicks int
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/google/uuid"
+)
+
+// URLRevision — прежнее состояние изменяемых полей ссылки. Ревизия
+// записывается при каждом изменении ссылки и больше не меняется.
+type URLRevision struct {
+	ID          uuid.UUID
+	URLID       uuid.UUID
+	OriginalURL string
+	ExpiresAt   *time.Time
+	MaxClicks   *int
+	// RedirectCode и NoIndex — настройки перенаправления, см. URL.
+	RedirectCode int
+	NoIndex      bool
+	// CreatedAt — момент, когда это состояние было заменено.
+	CreatedAt time.Time
+}
+
+// URLChange описывает изменение ссылки: nil-поля не меняются, а флаги
+// Clear* снимают соответствующее ограничение.
+type URLChange struct {
+	OriginalURL    *string
+	ExpiresAt      *time.Time
+	ClearExpiresAt bool
+	MaxClicks      *int
+	ClearMaxClicks bool
+	RedirectCode   *int
+	NoIndex        *bool
+}
+
+func (c URLChange) IsEmpty() bool {
+	return c.OriginalURL == nil && c.ExpiresAt == nil && !c.ClearExpiresAt && c.MaxClicks == nil && !c.ClearMaxClicks &&
+		c.RedirectCode == nil && c.NoIndex == nil
+}
+
+// Revise применяет изменение к копии ссылки и возвращает копию вместе с
+// ревизией прежнего состояния. Проверяются только изменяемые поля.
+func (u *URL) Revise(c URLChange, now time.Time) (*URL, *URLRevision, error) {
+	revised := *u
+
+	if c.OriginalURL != nil {
+		original := strings.TrimSpace(*c.OriginalURL)
+		if original == "" {
+			return nil, nil, errs.ValidationError("empty url")
+		}
+		revised.OriginalURL = original
+	}
+
+	expiresAt, maxClicks := revised.ExpiresAt, revised.MaxClicks
+	switch {
+	case c.ClearExpiresAt:
+		expiresAt = nil
+	case c.ExpiresAt != nil:
+		expiresAt = c.ExpiresAt
+	}
+	switch {
+	case c.ClearMaxClicks:
+		maxClicks = nil
+	case c.MaxClicks != nil:
+		maxClicks = c.MaxClicks
+	}
+	// Не изменённое ограничение могло уже истечь, поэтому проверяются только
+	// новые значения.
+	if err := (&URL{}).SetLimits(c.ExpiresAt, c.MaxClicks, now); err != nil {
+		return nil, nil, err
+	}
+	revised.ExpiresAt, revised.MaxClicks = expiresAt, maxClicks
+
+	if c.RedirectCode != nil || c.NoIndex != nil {
+		code, noIndex := revised.RedirectCode, revised.NoIndex
+		if c.RedirectCode != nil {
+			code = *c.RedirectCode
+		}
+		if c.NoIndex != nil {
+			noIndex = *c.NoIndex
+		}
+		if err := revised.SetRedirect(code, noIndex); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	revised.UpdatedAt = &now
+	return &revised, u.snapshot(now), nil
+}
+
+// Restore возвращает ссылку к состоянию ревизии rev. Значения ревизии
+// восстанавливаются как есть, без проверок Revise.
+func (u *URL) Restore(rev *URLRevision, now time.Time) (*URL, *URLRevision) {
+	restored := *u
+	restored.OriginalURL = rev.OriginalURL
+	restored.ExpiresAt = rev.ExpiresAt
+	restored.MaxClicks = rev.MaxClicks
+	restored.RedirectCode = rev.RedirectCode
+	restored.NoIndex = rev.NoIndex
+	restored.UpdatedAt = &now
+	return &restored, u.snapshot(now)
+}
+
+// ApplyEdits переносит на u изменяемые поля ссылки from. Хранилища применяют
+// правку так, чтобы не затереть счётчик переходов и пометку об удалении,
+// изменившиеся после чтения ссылки.
+func (u *URL) ApplyEdits(from *URL) {
+	u.OriginalURL = from.OriginalURL
+	u.ExpiresAt = from.ExpiresAt
+	u.MaxClicks = from.MaxClicks
+	u.RedirectCode = from.RedirectCode
+	u.NoIndex = from.NoIndex
+	u.UpdatedAt = from.UpdatedAt
+}
+
+func (u *URL) snapshot(now time.Time) *URLRevision {
+	return &URLRevision{
+		ID:           uuid.Must(uuid.NewV7()),
+		URLID:        u.ID,
+		OriginalURL:  u.OriginalURL,
+		ExpiresAt:    u.ExpiresAt,
+		MaxClicks:    u.MaxClicks,
+		RedirectCode: u.RedirectCode,
+		NoIndex:      u.NoIndex,
+		CreatedAt:    now,
+	}
+}
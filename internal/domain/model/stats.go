@@ -0,0 +1,6 @@
+package model
+
+type Stats struct {
+	URLs  int
+	Users int
+}
@@ -13,11 +13,22 @@ type URL struct {
 	Hash          string
 	OriginalURL   string
 	CorrelationID *string
+	UserID        *uuid.UUID
 	CreatedAt     time.Time
 	UpdatedAt     *time.Time
+	IsDeleted     bool
+	DeletedAt     *time.Time
+	ExpiresAt     *time.Time
+	MaxClicks     *int
+	// Clicks считается только для ссылок с MaxClicks.
+	Clicks int
+	// RedirectCode — код перенаправления; 0 — код по умолчанию из настроек.
+	RedirectCode int
+	// NoIndex просит поисковики не индексировать ссылку.
+	NoIndex bool
 }
 
-func NewURL(original string, hash string, correlationID *string) (*URL, error) {
+func NewURL(original string, hash string, correlationID *string, userID *uuid.UUID, createdAt time.Time) (*URL, error) {
 	original = strings.TrimSpace(original)
 	hash = strings.TrimSpace(hash)
 
@@ -33,6 +44,82 @@ func NewURL(original string, hash string, correlationID *string) (*URL, error) {
 		OriginalURL:   original,
 		Hash:          hash,
 		CorrelationID: correlationID,
-		CreatedAt:     time.Now(),
+		UserID:        userID,
+		CreatedAt:     createdAt,
 	}, nil
 }
+
+// SetLimits задаёт срок жизни ссылки и максимальное число переходов.
+func (u *URL) SetLimits(expiresAt *time.Time, maxClicks *int, now time.Time) error {
+	if expiresAt != nil && !expiresAt.After(now) {
+		return errs.ValidationError("expires_at must be in the future")
+	}
+	if maxClicks != nil && *maxClicks < 1 {
+		return errs.ValidationError("max_clicks must be positive")
+	}
+
+	u.ExpiresAt = expiresAt
+	u.MaxClicks = maxClicks
+	return nil
+}
+
+// SetRedirect задаёт код перенаправления (0 — по умолчанию) и запрет
+// индексации.
+func (u *URL) SetRedirect(code int, noIndex bool) error {
+	if code != 0 && !IsRedirectCode(code) {
+		return errs.ValidationError("redirect_code must be 301, 302, 307 or 308")
+	}
+
+	u.RedirectCode = code
+	u.NoIndex = noIndex
+	return nil
+}
+
+// IsRedirectCode сообщает, что code — допустимый код перенаправления.
+func IsRedirectCode(code int) bool {
+	switch code {
+	case 301, 302, 307, 308:
+		return true
+	}
+	return false
+}
+
+// IsPermanentRedirect сообщает, что перенаправление с кодом code клиенты
+// вправе запомнить.
+func IsPermanentRedirect(code int) bool {
+	return code == 301 || code == 308
+}
+
+// IsExpired сообщает, что срок жизни ссылки истёк или лимит переходов исчерпан.
+func (u *URL) IsExpired(now time.Time) bool {
+	if u.ExpiresAt != nil && !now.Before(*u.ExpiresAt) {
+		return true
+	}
+	return u.MaxClicks != nil && u.Clicks >= *u.MaxClicks
+}
+
+// URLStatus — состояние ссылки с точки зрения перехода по ней.
+type URLStatus string
+
+const (
+	URLActive  URLStatus = "active"
+	URLExpired URLStatus = "expired"
+	URLDeleted URLStatus = "deleted"
+)
+
+func (u *URL) Status(now time.Time) URLStatus {
+	switch {
+	case u.IsDeleted:
+		return URLDeleted
+	case u.IsExpired(now):
+		return URLExpired
+	}
+	return URLActive
+}
+
+// URLInfo — ссылка с общим числом переходов и состоянием на момент запроса.
+type URLInfo struct {
+	*URL
+	TotalClicks int
+	Status      URLStatus
+}
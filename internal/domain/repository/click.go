@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type ClickRepository interface {
+	SaveBatch(ctx context.Context, clicks []*model.Click) error
+	// Stats считает переходы за всё время и временные ряды по дням и часам
+	// начиная с q.DailyFrom и q.HourlyFrom. Уникальные посетители
+	// различаются по паре обезличенный IP + User-Agent.
+	Stats(ctx context.Context, q model.ClickStatsQuery) (*model.ClickStats, error)
+	// CountByURL возвращает число переходов по каждой из ссылок urlIDs;
+	// ссылки без переходов в результат не попадают.
+	CountByURL(ctx context.Context, urlIDs []uuid.UUID) (map[uuid.UUID]int, error)
+}
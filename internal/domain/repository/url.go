@@ -2,13 +2,43 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/google/uuid"
 )
 
 type URLRepository interface {
 	Create(ctx context.Context, url *model.URL) error
-	CreateBatch(ctx context.Context, urls []*model.URL) error
+	// CreateBatch сохраняет записи, которые не конфликтуют с уже
+	// существующими, и возвращает ошибку для каждой записи: nil — сохранена,
+	// DuplicateEntryError — адрес уже сокращён, HashConflictError — код занят.
+	// Общая ошибка означает, что не сохранено ничего.
+	CreateBatch(ctx context.Context, urls []*model.URL) ([]error, error)
 	FindByHash(ctx context.Context, hash string) (*model.URL, error)
+	// FindByOriginalURL возвращает неудалённую ссылку на originalURL или
+	// nil без ошибки, если такой нет.
 	FindByOriginalURL(ctx context.Context, originalURL string) (*model.URL, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*model.URL, error)
+	// FindPage возвращает страницу ссылок по курсору q.After; пустой
+	// результат означает, что ссылки закончились.
+	FindPage(ctx context.Context, q model.URLPageQuery) ([]*model.URL, error)
+	// DeleteBatch помечает удалёнными ссылки byUser[userID], принадлежащие
+	// userID, одним запросом; чужие и уже удалённые коды пропускаются.
+	DeleteBatch(ctx context.Context, byUser map[uuid.UUID][]string) error
+	// Update сохраняет изменяемые поля ссылки url (адрес, срок жизни,
+	// лимит переходов, UpdatedAt) и вместе с ними ревизию rev прежнего
+	// состояния. Если адрес уже сокращён другой ссылкой, возвращает
+	// DuplicateEntryError, если ссылки нет — NotFoundError.
+	Update(ctx context.Context, url *model.URL, rev *model.URLRevision) error
+	// FindRevisions возвращает ревизии ссылки urlID, новые первыми.
+	FindRevisions(ctx context.Context, urlID uuid.UUID) ([]*model.URLRevision, error)
+	CountURLs(ctx context.Context) (int, error)
+	CountUsers(ctx context.Context) (int, error)
+	// RegisterClick атомарно увеличивает счётчик переходов ссылки с лимитом.
+	// Возвращает false, если лимит уже исчерпан.
+	RegisterClick(ctx context.Context, hash string) (bool, error)
+	// PurgeExpired убирает ссылки, срок жизни которых истёк к моменту now
+	// или лимит переходов которых исчерпан, и возвращает их количество.
+	PurgeExpired(ctx context.Context, now time.Time) (int, error)
 }
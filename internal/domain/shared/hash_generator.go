@@ -0,0 +1,10 @@
+package shared
+
+import "context"
+
+// HashGenerator выдаёт короткие коды ссылок. attempt — номер попытки,
+// начиная с нуля: детерминированные генераторы должны давать на разных
+// попытках разные коды, чтобы повтор после коллизии имел смысл.
+type HashGenerator interface {
+	Generate(ctx context.Context, originalURL string, attempt int) (string, error)
+}
@@ -1,8 +1,18 @@
 package shared
 
+import "context"
+
 type Logger interface {
 	Debug(message string, args ...any)
 	Info(message string, args ...any)
 	Error(message string, args ...any)
+	// DebugContext, InfoContext и ErrorContext дополнительно берут из ctx
+	// сведения о запросе, например идентификатор трассы.
+	DebugContext(ctx context.Context, message string, args ...any)
+	InfoContext(ctx context.Context, message string, args ...any)
+	ErrorContext(ctx context.Context, message string, args ...any)
+	// With возвращает логгер, добавляющий args к каждой записи. Закрывать
+	// его не нужно: ресурсами владеет исходный логгер.
+	With(args ...any) Logger
 	Close() error
 }
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type userIDKey struct{}
+
+type issuedKey struct{}
+
+func WithUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// WithIssuedUserID кладёт в контекст идентификатор, выпущенный в рамках
+// текущего запроса: клиент пришёл без действующего токена.
+func WithIssuedUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(WithUserID(ctx, userID), issuedKey{}, true)
+}
+
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(uuid.UUID)
+	return userID, ok
+}
+
+// AuthenticatedUserID возвращает идентификатор только если клиент предъявил
+// действующий токен.
+func AuthenticatedUserID(ctx context.Context) (uuid.UUID, bool) {
+	if issued, _ := ctx.Value(issuedKey{}).(bool); issued {
+		return uuid.Nil, false
+	}
+	return UserIDFromContext(ctx)
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const TokenTTL = 30 * 24 * time.Hour
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type Claims struct {
+	jwt.RegisteredClaims
+	UserID string `json:"user_id"`
+}
+
+type TokenManager struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewTokenManager(secret string) *TokenManager {
+	return &TokenManager{secret: []byte(secret), ttl: TokenTTL}
+}
+
+func (m *TokenManager) Issue(userID uuid.UUID) (string, error) {
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		UserID: userID.String(),
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+}
+
+// Parse возвращает errs.UnauthorizedError, если подпись токена не сходится
+// (токен подделан), и ErrInvalidToken для просроченных или битых токенов,
+// которые достаточно перевыпустить.
+func (m *TokenManager) Parse(token string) (uuid.UUID, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenSignatureInvalid) || errors.Is(err, jwt.ErrTokenUnverifiable) {
+			return uuid.Nil, errs.UnauthorizedError("invalid token signature")
+		}
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return uuid.Nil, errs.UnauthorizedError("invalid user id")
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,24 @@
+package certs
+
+import (
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSelfSigned(t *testing.T) {
+	cert, err := GenerateSelfSigned("shortener.local", "10.0.0.1")
+	require.NoError(t, err)
+	require.NotNil(t, cert.Leaf)
+
+	for _, host := range []string{"shortener.local", "localhost", "10.0.0.1", "127.0.0.1"} {
+		assert.NoError(t, cert.Leaf.VerifyHostname(host), host)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert.Leaf)
+	_, err = cert.Leaf.Verify(x509.VerifyOptions{Roots: pool, DNSName: "localhost"})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,49 @@
+// Package hashgen содержит генераторы коротких кодов ссылок.
+package hashgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+const alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+const (
+	Random   = "random"
+	Sequence = "sequence"
+	Hashids  = "hashids"
+	Content  = "content"
+)
+
+// MaxHashidsLength — предел длины для hashids: 62^10 ещё помещается в uint64
+// с запасом для арифметики по модулю.
+const MaxHashidsLength = 10
+
+// encode записывает n в системе счисления по основанию len(digits),
+// дополняя результат слева нулевой цифрой до minLength.
+func encode(n uint64, digits string, minLength int) string {
+	base := uint64(len(digits))
+
+	var b []byte
+	for n > 0 {
+		b = append(b, digits[n%base])
+		n /= base
+	}
+	for len(b) < minLength {
+		b = append(b, digits[0])
+	}
+
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+// Kinds перечисляет допустимые значения HASH_GENERATOR.
+func Kinds() []string {
+	return []string{Random, Sequence, Hashids, Content}
+}
+
+func unknownKind(kind string) error {
+	return fmt.Errorf("unknown hash generator %q: expected one of %s", kind, strings.Join(Kinds(), ", "))
+}
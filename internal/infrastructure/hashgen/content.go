@@ -0,0 +1,45 @@
+package hashgen
+
+import (
+	"context"
+	"crypto/sha256"
+	"math/big"
+	"strconv"
+
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+)
+
+// ContentGenerator выводит код из SHA-256 исходного адреса, поэтому один и
+// тот же адрес всегда получает один и тот же код. При повторе после
+// коллизии к адресу добавляется номер попытки.
+type ContentGenerator struct {
+	length int
+}
+
+var _ shared.HashGenerator = ContentGenerator{}
+
+// MaxContentLength — длина base62-записи SHA-256.
+const MaxContentLength = 43
+
+func NewContentGenerator(length int) ContentGenerator {
+	return ContentGenerator{length: min(length, MaxContentLength)}
+}
+
+func (g ContentGenerator) Generate(_ context.Context, originalURL string, attempt int) (string, error) {
+	input := originalURL
+	if attempt > 0 {
+		input += "#" + strconv.Itoa(attempt)
+	}
+
+	sum := sha256.Sum256([]byte(input))
+	n := new(big.Int).SetBytes(sum[:])
+	base := big.NewInt(int64(len(alphabet)))
+	mod := new(big.Int)
+
+	out := make([]byte, 0, g.length)
+	for len(out) < g.length {
+		n.DivMod(n, base, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	return string(out), nil
+}
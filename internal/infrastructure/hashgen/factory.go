@@ -0,0 +1,20 @@
+package hashgen
+
+import "github.com/amberdance/url-shortener/internal/domain/shared"
+
+// New создаёт генератор вида kind. Источник seq нужен только генераторам
+// sequence и hashids.
+func New(kind string, length int, salt string, seq SequenceSource) (shared.HashGenerator, error) {
+	switch kind {
+	case Random:
+		return NewRandomGenerator(length), nil
+	case Sequence:
+		return NewSequenceGenerator(seq, length), nil
+	case Hashids:
+		return NewHashidsGenerator(seq, length, salt), nil
+	case Content:
+		return NewContentGenerator(length), nil
+	default:
+		return nil, unknownKind(kind)
+	}
+}
@@ -0,0 +1,96 @@
+package hashgen
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerators_Length(t *testing.T) {
+	for _, kind := range Kinds() {
+		t.Run(kind, func(t *testing.T) {
+			g, err := New(kind, 8, "salt", NewCounter(0))
+			require.NoError(t, err)
+
+			hash, err := g.Generate(context.Background(), "https://example.com", 0)
+			require.NoError(t, err)
+			assert.Len(t, hash, 8)
+			for _, r := range hash {
+				assert.Contains(t, alphabet, string(r))
+			}
+		})
+	}
+}
+
+func TestNew_UnknownKind(t *testing.T) {
+	_, err := New("md5", 8, "", NewCounter(0))
+	assert.Error(t, err)
+}
+
+func TestContentGenerator_Deterministic(t *testing.T) {
+	g := NewContentGenerator(10)
+	ctx := context.Background()
+
+	first, _ := g.Generate(ctx, "https://example.com", 0)
+	second, _ := g.Generate(ctx, "https://example.com", 0)
+	retry, _ := g.Generate(ctx, "https://example.com", 1)
+	other, _ := g.Generate(ctx, "https://example.org", 0)
+
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, retry)
+	assert.NotEqual(t, first, other)
+}
+
+func TestSequentialGenerators_Distinct(t *testing.T) {
+	ctx := context.Background()
+	gens := map[string]func() (string, error){}
+
+	seq := NewSequenceGenerator(NewCounter(0), 4)
+	gens[Sequence] = func() (string, error) { return seq.Generate(ctx, "", 0) }
+	ids := NewHashidsGenerator(NewCounter(0), 6, "salt")
+	gens[Hashids] = func() (string, error) { return ids.Generate(ctx, "", 0) }
+
+	for kind, next := range gens {
+		t.Run(kind, func(t *testing.T) {
+			seen := make(map[string]struct{})
+			for range 10_000 {
+				hash, err := next()
+				require.NoError(t, err)
+				_, dup := seen[hash]
+				require.False(t, dup, "duplicate %s", hash)
+				seen[hash] = struct{}{}
+			}
+		})
+	}
+}
+
+func TestHashidsGenerator_SaltChangesCodes(t *testing.T) {
+	ctx := context.Background()
+	a, _ := NewHashidsGenerator(NewCounter(0), 6, "one").Generate(ctx, "", 0)
+	b, _ := NewHashidsGenerator(NewCounter(0), 6, "two").Generate(ctx, "", 0)
+	assert.NotEqual(t, a, b)
+}
+
+func TestFileCounter_ContinuesAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "db.json.seq")
+
+	c, err := NewFileCounter(path, 100, 10)
+	require.NoError(t, err)
+	var last uint64
+	for range 15 {
+		last, err = c.Next(ctx)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, uint64(115), last)
+
+	// Начальное значение учитывается, только пока файла нет.
+	reopened, err := NewFileCounter(path, 0, 10)
+	require.NoError(t, err)
+	n, err := reopened.Next(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, n, last, "номера не повторяются после перезапуска")
+}
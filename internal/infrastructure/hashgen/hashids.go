@@ -0,0 +1,72 @@
+package hashgen
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"math/bits"
+	"math/rand/v2"
+
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+)
+
+// HashidsGenerator, как и SequenceGenerator, кодирует очередной номер, но
+// скрывает порядок: номер взаимно однозначно перемешивается по модулю
+// 62^length, а алфавит переставляется в зависимости от соли. Без соли
+// соседние коды выглядят несвязанными.
+type HashidsGenerator struct {
+	source   SequenceSource
+	length   int
+	alphabet string
+	modulus  uint64
+	factor   uint64
+	offset   uint64
+}
+
+var _ shared.HashGenerator = HashidsGenerator{}
+
+// NewHashidsGenerator создаёт генератор кодов длины length, не больше
+// MaxHashidsLength.
+func NewHashidsGenerator(s SequenceSource, length int, salt string) HashidsGenerator {
+	length = min(length, MaxHashidsLength)
+	seed := sha256.Sum256([]byte(salt))
+
+	modulus := uint64(1)
+	for range length {
+		modulus *= uint64(len(alphabet))
+	}
+
+	// Множитель должен быть взаимно прост с 62^length, то есть нечётен и не
+	// кратен 31, тогда умножение по модулю — биекция.
+	factor := binary.BigEndian.Uint64(seed[0:8])%modulus | 1
+	for factor%31 == 0 {
+		factor += 2
+	}
+
+	shuffled := []byte(alphabet)
+	rnd := rand.New(rand.NewChaCha8(seed))
+	rnd.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	return HashidsGenerator{
+		source:   s,
+		length:   length,
+		alphabet: string(shuffled),
+		modulus:  modulus,
+		factor:   factor,
+		offset:   binary.BigEndian.Uint64(seed[8:16]) % modulus,
+	}
+}
+
+func (g HashidsGenerator) Generate(ctx context.Context, _ string, _ int) (string, error) {
+	n, err := g.source.Next(ctx)
+	if err != nil {
+		return "", err
+	}
+	return encode(g.permute(n), g.alphabet, g.length), nil
+}
+
+func (g HashidsGenerator) permute(n uint64) uint64 {
+	hi, lo := bits.Mul64(n%g.modulus, g.factor)
+	_, rem := bits.Div64(hi, lo, g.modulus)
+	return (rem + g.offset) % g.modulus
+}
@@ -0,0 +1,42 @@
+package hashgen
+
+import (
+	"context"
+	"crypto/rand"
+
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+)
+
+// RandomGenerator выдаёт случайные коды из crypto/rand.
+type RandomGenerator struct {
+	length int
+}
+
+var _ shared.HashGenerator = RandomGenerator{}
+
+func NewRandomGenerator(length int) RandomGenerator {
+	return RandomGenerator{length: length}
+}
+
+func (g RandomGenerator) Generate(_ context.Context, _ string, _ int) (string, error) {
+	// Байты от 248 отбрасываются: 248 = 4*62, так все символы равновероятны.
+	const limit = 256 - 256%len(alphabet)
+
+	out := make([]byte, 0, g.length)
+	buf := make([]byte, g.length*2)
+	for len(out) < g.length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			out = append(out, alphabet[int(b)%len(alphabet)])
+			if len(out) == g.length {
+				break
+			}
+		}
+	}
+	return string(out), nil
+}
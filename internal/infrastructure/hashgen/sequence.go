@@ -0,0 +1,154 @@
+package hashgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SequenceSource выдаёт возрастающие номера.
+type SequenceSource interface {
+	Next(ctx context.Context) (uint64, error)
+}
+
+// Counter — SequenceSource в памяти процесса.
+type Counter struct {
+	n atomic.Uint64
+}
+
+// NewCounter создаёт счётчик, первый номер которого start+1. Номера не
+// переживают перезапуск; для файлового хранилища нужен FileCounter.
+func NewCounter(start uint64) *Counter {
+	c := &Counter{}
+	c.n.Store(start)
+	return c
+}
+
+func (c *Counter) Next(_ context.Context) (uint64, error) {
+	return c.n.Add(1), nil
+}
+
+// FileCounter — SequenceSource, сохраняющий в файл верхнюю границу выданных
+// номеров. Границы резервируются блоками по step номеров, поэтому файл
+// переписывается раз в step вызовов Next, а после перезапуска неиспользованный
+// остаток блока пропускается.
+type FileCounter struct {
+	mu    sync.Mutex
+	path  string
+	step  uint64
+	n     uint64
+	limit uint64
+}
+
+// NewFileCounter читает границу из path. Если файла ещё нет, счёт
+// начинается с start.
+func NewFileCounter(path string, start, step uint64) (*FileCounter, error) {
+	if step == 0 {
+		return nil, errors.New("counter step must be positive")
+	}
+
+	n := start
+	b, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		n, err = strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse counter %s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	return &FileCounter{path: path, step: step, n: n, limit: n}, nil
+}
+
+func (c *FileCounter) Next(_ context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.n == c.limit {
+		if err := c.store(c.limit + c.step); err != nil {
+			return 0, fmt.Errorf("failed to save counter: %w", err)
+		}
+		c.limit += c.step
+	}
+	c.n++
+	return c.n, nil
+}
+
+// store атомарно записывает границу limit: через временный файл и
+// переименование.
+func (c *FileCounter) store(limit uint64) error {
+	tmp := c.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.WriteString(strconv.FormatUint(limit, 10) + "\n")
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, c.path); err != nil {
+		return err
+	}
+	if d, err := os.Open(filepath.Dir(c.path)); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
+
+// PostgresSequence берёт номера из последовательности PostgreSQL.
+type PostgresSequence struct {
+	pool *pgxpool.Pool
+	name string
+}
+
+func NewPostgresSequence(pool *pgxpool.Pool, name string) *PostgresSequence {
+	return &PostgresSequence{pool: pool, name: name}
+}
+
+func (s *PostgresSequence) Next(ctx context.Context) (uint64, error) {
+	var n int64
+	err := s.pool.QueryRow(ctx, "select nextval($1::regclass)", s.name).Scan(&n)
+	return uint64(n), err
+}
+
+// SequenceGenerator кодирует очередной номер в base62. Коды получаются
+// короткими и не повторяются, но легко перебираются.
+type SequenceGenerator struct {
+	source SequenceSource
+	length int
+}
+
+var _ shared.HashGenerator = SequenceGenerator{}
+
+func NewSequenceGenerator(s SequenceSource, minLength int) SequenceGenerator {
+	return SequenceGenerator{source: s, length: minLength}
+}
+
+func (g SequenceGenerator) Generate(ctx context.Context, _ string, _ int) (string, error) {
+	n, err := g.source.Next(ctx)
+	if err != nil {
+		return "", err
+	}
+	return encode(n, alphabet, g.length), nil
+}
@@ -1,52 +1,90 @@
 package logging
 
 import (
-	"log"
+	"context"
+	"io"
 	"log/slog"
 	"os"
 	"time"
 
-	"github.com/amberdance/url-shortener/internal/config"
 	"github.com/amberdance/url-shortener/internal/domain/shared"
 	"github.com/lmittmann/tint"
 )
 
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Logger пишет каждую запись в stdout и, если задан файл, в файл с
+// ротацией. Производные логгеры из With разделяют с исходным файл, но не
+// закрывают его.
 type Logger struct {
-	file *os.File
+	file io.Closer
 	logs []*slog.Logger
 }
 
 var _ shared.Logger = (*Logger)(nil)
 
-func NewLogger() *Logger {
-	cfg := config.GetConfig()
+type options struct {
+	format   string
+	path     string
+	rotation Rotation
+}
+
+type Option func(*options)
 
-	f, err := os.OpenFile("./logs/app.log", os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0666)
-	if err != nil {
-		log.Fatalln(err)
+// WithFormat задаёт формат записей: text (цветной вывод tint) или json.
+func WithFormat(format string) Option {
+	return func(o *options) { o.format = format }
+}
+
+// WithFile включает запись лога в файл path с ротацией r.
+func WithFile(path string, r Rotation) Option {
+	return func(o *options) {
+		o.path = path
+		o.rotation = r
+	}
+}
+
+func NewLogger(logLevel string, opts ...Option) (*Logger, error) {
+	o := options{format: FormatText}
+	for _, opt := range opts {
+		opt(&o)
 	}
-	defer f.Close()
 
 	level := slog.LevelDebug
-	switch cfg.LogLevel {
+	switch logLevel {
 	case "info":
 		level = slog.LevelInfo
 	case "error":
 		level = slog.LevelError
 	}
 
-	opts := &tint.Options{Level: level, TimeFormat: time.DateTime}
-
-	return &Logger{
-		file: f,
-		logs: []*slog.Logger{
-			slog.New(tint.NewHandler(os.Stdout, opts)),
-			slog.New(tint.NewHandler(f, &tint.Options{
-				Level:      level,
-				TimeFormat: time.DateTime,
-			})),
-		},
+	l := &Logger{
+		logs: []*slog.Logger{slog.New(newHandler(os.Stdout, o.format, level, true))},
+	}
+
+	if o.path != "" {
+		f, err := openRotatingFile(o.path, o.rotation)
+		if err != nil {
+			return nil, err
+		}
+		l.file = f
+		l.logs = append(l.logs, slog.New(newHandler(f, o.format, level, false)))
+	}
+
+	return l, nil
+}
+
+func newHandler(w io.Writer, format string, level slog.Level, color bool) slog.Handler {
+	var h slog.Handler
+	if format == FormatJSON {
+		h = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	} else {
+		h = tint.NewHandler(w, &tint.Options{Level: level, TimeFormat: time.DateTime, NoColor: !color})
 	}
+	return traceHandler{h}
 }
 
 func (l *Logger) Close() error {
@@ -56,12 +94,40 @@ func (l *Logger) Close() error {
 	return nil
 }
 
-func (l *Logger) log(fn func(*slog.Logger, string, ...any), msg string, args ...any) {
+func (l *Logger) With(args ...any) shared.Logger {
+	logs := make([]*slog.Logger, len(l.logs))
+	for i, lg := range l.logs {
+		logs[i] = lg.With(args...)
+	}
+	return &Logger{logs: logs}
+}
+
+func (l *Logger) log(ctx context.Context, level slog.Level, msg string, args ...any) {
 	for _, lg := range l.logs {
-		fn(lg, msg, args...)
+		lg.Log(ctx, level, msg, args...)
 	}
 }
 
-func (l *Logger) Info(msg string, args ...any)  { l.log((*slog.Logger).Info, msg, args...) }
-func (l *Logger) Debug(msg string, args ...any) { l.log((*slog.Logger).Debug, msg, args...) }
-func (l *Logger) Error(msg string, args ...any) { l.log((*slog.Logger).Error, msg, args...) }
+func (l *Logger) Debug(msg string, args ...any) {
+	l.log(context.Background(), slog.LevelDebug, msg, args...)
+}
+
+func (l *Logger) Info(msg string, args ...any) {
+	l.log(context.Background(), slog.LevelInfo, msg, args...)
+}
+
+func (l *Logger) Error(msg string, args ...any) {
+	l.log(context.Background(), slog.LevelError, msg, args...)
+}
+
+func (l *Logger) DebugContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelDebug, msg, args...)
+}
+
+func (l *Logger) InfoContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelInfo, msg, args...)
+}
+
+func (l *Logger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	l.log(ctx, slog.LevelError, msg, args...)
+}
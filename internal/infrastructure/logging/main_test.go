@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestLogger_JSONFileWithTraceAndFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	l, err := NewLogger("info", WithFormat(FormatJSON), WithFile(path, Rotation{}))
+	require.NoError(t, err)
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "request")
+	l.With("component", "test").InfoContext(ctx, "hello", "n", 1)
+	l.Debug("skipped")
+	span.End()
+	require.NoError(t, l.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var records []map[string]any
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var rec map[string]any
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &rec))
+		records = append(records, rec)
+	}
+
+	require.Len(t, records, 1, "debug ниже уровня info")
+	assert.Equal(t, "hello", records[0]["msg"])
+	assert.Equal(t, "test", records[0]["component"])
+	assert.Equal(t, span.SpanContext().TraceID().String(), records[0]["trace_id"])
+}
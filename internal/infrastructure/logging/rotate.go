@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotation задаёт, когда файл лога переименовывается в резервную копию и
+// начинается заново. Нулевые значения отключают соответствующее условие.
+type Rotation struct {
+	// MaxSize — размер файла в байтах, после которого он ротируется.
+	MaxSize int64
+	// Every — как часто ротировать файл независимо от размера.
+	Every time.Duration
+	// MaxBackups — сколько резервных копий хранить; 0 — все.
+	MaxBackups int
+}
+
+const backupTimeFormat = "20060102T150405.000"
+
+// rotatingFile — io.WriteCloser поверх файла лога. Резервные копии
+// получают имя вида app-20260102T150405.000.log рядом с исходным файлом.
+type rotatingFile struct {
+	mu     sync.Mutex
+	path   string
+	r      Rotation
+	file   *os.File
+	size   int64
+	opened time.Time
+	now    func() time.Time
+}
+
+func openRotatingFile(path string, r Rotation) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	f := &rotatingFile{path: path, r: r, now: time.Now}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.due(len(p)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// due сообщает, нужно ли ротировать файл перед записью n байт. Пустой
+// файл не ротируется, даже если одна запись больше MaxSize.
+func (f *rotatingFile) due(n int) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.r.MaxSize > 0 && f.size+int64(n) > f.r.MaxSize {
+		return true
+	}
+	return f.r.Every > 0 && f.now().Sub(f.opened) >= f.r.Every
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = fi.Size()
+	f.opened = f.now()
+	return nil
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext)
+	backup := base + "-" + f.now().Format(backupTimeFormat) + ext
+	if err := os.Rename(f.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.prune(base, ext)
+	return nil
+}
+
+// prune удаляет самые старые резервные копии сверх MaxBackups. Копиями
+// считаются только файлы, в имени которых после base- стоит метка в формате
+// backupTimeFormat; метка сортируется лексикографически.
+func (f *rotatingFile) prune(base, ext string) {
+	if f.r.MaxBackups <= 0 {
+		return
+	}
+
+	matches, err := filepath.Glob(base + "-*" + ext)
+	if err != nil {
+		return
+	}
+	backups := slices.DeleteFunc(matches, func(path string) bool {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(path, base+"-"), ext)
+		_, err := time.Parse(backupTimeFormat, stamp)
+		return err != nil
+	})
+	if len(backups) <= f.r.MaxBackups {
+		return
+	}
+	slices.Sort(backups)
+	for _, old := range backups[:len(backups)-f.r.MaxBackups] {
+		_ = os.Remove(old)
+	}
+}
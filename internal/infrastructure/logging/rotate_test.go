@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func backups(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestRotatingFile_BySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	// Файлы с похожим именем, но не резервные копии, не трогаются.
+	foreign := []string{filepath.Join(dir, "app-errors.log"), filepath.Join(dir, "app-0.log")}
+	for _, name := range foreign {
+		require.NoError(t, os.WriteFile(name, nil, 0644))
+	}
+
+	clock := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	f, err := openRotatingFile(path, Rotation{MaxSize: 10, MaxBackups: 2})
+	require.NoError(t, err)
+	f.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	defer f.Close()
+
+	for range 5 {
+		_, err := f.Write([]byte("0123456789"))
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, backups(t, dir), 2+len(foreign), "лишние копии удаляются")
+	for _, name := range foreign {
+		assert.FileExists(t, name)
+	}
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(data))
+}
+
+func TestRotatingFile_ByTime(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	clock := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	f, err := openRotatingFile(path, Rotation{Every: time.Hour})
+	require.NoError(t, err)
+	f.now = func() time.Time { return clock }
+	f.opened = clock
+	defer f.Close()
+
+	_, err = f.Write([]byte("first\n"))
+	require.NoError(t, err)
+	_, err = f.Write([]byte("second\n"))
+	require.NoError(t, err)
+	assert.Empty(t, backups(t, dir))
+
+	clock = clock.Add(time.Hour)
+	_, err = f.Write([]byte("third\n"))
+	require.NoError(t, err)
+
+	require.Len(t, backups(t, dir), 1)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "third\n", string(data))
+}
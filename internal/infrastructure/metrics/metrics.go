@@ -0,0 +1,141 @@
+// Package metrics собирает метрики сервиса и отдаёт их в текстовом формате
+// Prometheus.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "shortener"
+
+// Metrics владеет собственным реестром, чтобы несколько экземпляров
+// приложения (например, в тестах) не конфликтовали в глобальном.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	redirects    *prometheus.CounterVec
+	linksCreated prometheus.Counter
+	batchSize    prometheus.Histogram
+	repoDuration *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		redirects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "redirects_total",
+			Help:      "Redirects served by status code.",
+		}, []string{"status"}),
+		linksCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "links_created_total",
+			Help:      "Short links stored.",
+		}),
+		batchSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "batch_size",
+			Help:      "Number of links per batch insert.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+		}),
+		repoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "Repository operation latency by backend and operation.",
+			Buckets:   []float64{.0001, .0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"backend", "operation"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.redirects,
+		m.linksCreated,
+		m.batchSize,
+		m.repoDuration,
+	)
+	return m
+}
+
+// Handler отдаёт метрики в текстовом формате Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveHTTP учитывает обработанный HTTP-запрос. Ответы с кодом
+// перенаправления дополнительно считаются в redirects_total.
+func (m *Metrics) ObserveHTTP(method, route string, status int, d time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(d.Seconds())
+
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		m.redirects.WithLabelValues(code).Inc()
+	}
+}
+
+// WatchFileStorage публикует размер журнала файлового хранилища.
+func (m *Metrics) WatchFileStorage(s *storage.FileStorage) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "file_storage_size_bytes",
+		Help:      "Size of the file storage journal.",
+	}, func() float64 {
+		size, err := s.Size()
+		if err != nil {
+			return 0
+		}
+		return float64(size)
+	}))
+}
+
+// WatchURLCache публикует счётчики кэша поиска ссылок по коду.
+func (m *Metrics) WatchURLCache(c *url.CachedURLRepository) {
+	lookup := func(result string, value func(url.CacheStats) uint64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "url_cache_lookups_total",
+			Help:        "URL cache lookups by result: hit, negative_hit or miss.",
+			ConstLabels: prometheus.Labels{"result": result},
+		}, func() float64 { return float64(value(c.Stats())) })
+	}
+
+	m.registry.MustRegister(
+		lookup("hit", func(s url.CacheStats) uint64 { return s.Hits }),
+		lookup("negative_hit", func(s url.CacheStats) uint64 { return s.NegativeHits }),
+		lookup("miss", func(s url.CacheStats) uint64 { return s.Misses }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "url_cache_entries",
+			Help:      "Entries in the URL cache, found and missing.",
+		}, func() float64 { return float64(c.Stats().Entries) }),
+	)
+}
+
+func (m *Metrics) observeRepo(backend, operation string, started time.Time) {
+	m.repoDuration.WithLabelValues(backend, operation).Observe(time.Since(started).Seconds())
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newURL(t *testing.T, original, hash string) *model.URL {
+	m, err := model.NewURL(original, hash, nil, nil, time.Now())
+	require.NoError(t, err)
+	return m
+}
+
+func TestURLRepository_CountsCreatedLinks(t *testing.T) {
+	m := New()
+	repo := m.URLRepository(url.NewInMemoryURLRepository(storage.NewInMemoryStorage()), "memory")
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, newURL(t, "https://a.example", "a")))
+	itemErrs, err := repo.CreateBatch(ctx, []*model.URL{
+		newURL(t, "https://b.example", "b"),
+		newURL(t, "https://a.example", "c"),
+	})
+	require.NoError(t, err)
+	require.Len(t, itemErrs, 2)
+	_, err = repo.FindByHash(ctx, "a")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.linksCreated))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.batchSize))
+	assert.Equal(t, 3, testutil.CollectAndCount(m.repoDuration), "create, create_batch и find_by_hash")
+}
+
+func TestObserveHTTP_CountsRedirects(t *testing.T) {
+	m := New()
+	m.ObserveHTTP(http.MethodGet, "/{hash}", http.StatusTemporaryRedirect, time.Millisecond)
+	m.ObserveHTTP(http.MethodGet, "/{hash}", http.StatusNotFound, time.Millisecond)
+	m.ObserveHTTP(http.MethodPost, "/api/shorten", http.StatusCreated, time.Millisecond)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.redirects.WithLabelValues("307")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues(http.MethodGet, "/{hash}", "404")))
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `shortener_http_requests_total{method="POST",route="/api/shorten",status="201"} 1`)
+}
+
+func TestWatchFileStorage(t *testing.T) {
+	m := New()
+	s, err := storage.NewFileStorage(t.TempDir() + "/db.json")
+	require.NoError(t, err)
+	defer s.Close()
+	m.WatchFileStorage(s)
+
+	require.NoError(t, s.Put(newURL(t, "https://a.example", "a")))
+
+	size, err := s.Size()
+	require.NoError(t, err)
+	assert.Positive(t, size)
+
+	families, err := m.registry.Gather()
+	require.NoError(t, err)
+	var found bool
+	for _, f := range families {
+		if f.GetName() == "shortener_file_storage_size_bytes" {
+			found = true
+			assert.Equal(t, float64(size), f.GetMetric()[0].GetGauge().GetValue())
+		}
+	}
+	assert.True(t, found)
+}
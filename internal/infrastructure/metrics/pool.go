@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector снимает статистику пула pgx в момент сбора метрик.
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired      *prometheus.Desc
+	idle          *prometheus.Desc
+	constructing  *prometheus.Desc
+	total         *prometheus.Desc
+	max           *prometheus.Desc
+	acquires      *prometheus.Desc
+	emptyAcquires *prometheus.Desc
+	canceled      *prometheus.Desc
+	waitSeconds   *prometheus.Desc
+}
+
+// WatchPool публикует статистику пула соединений PostgreSQL.
+func (m *Metrics) WatchPool(pool *pgxpool.Pool) {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "pgxpool", name), help, nil, nil)
+	}
+
+	m.registry.MustRegister(&poolCollector{
+		pool:          pool,
+		acquired:      desc("acquired_conns", "Connections currently acquired."),
+		idle:          desc("idle_conns", "Idle connections in the pool."),
+		constructing:  desc("constructing_conns", "Connections being established."),
+		total:         desc("total_conns", "Total connections in the pool."),
+		max:           desc("max_conns", "Maximum pool size."),
+		acquires:      desc("acquire_total", "Successful connection acquisitions."),
+		emptyAcquires: desc("empty_acquire_total", "Acquisitions that waited for a connection."),
+		canceled:      desc("canceled_acquire_total", "Acquisitions canceled by context."),
+		waitSeconds:   desc("acquire_duration_seconds_total", "Total time spent acquiring connections."),
+	})
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.acquired, c.idle, c.constructing, c.total, c.max,
+		c.acquires, c.emptyAcquires, c.canceled, c.waitSeconds,
+	} {
+		ch <- d
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+
+	gauge(c.acquired, float64(s.AcquiredConns()))
+	gauge(c.idle, float64(s.IdleConns()))
+	gauge(c.constructing, float64(s.ConstructingConns()))
+	gauge(c.total, float64(s.TotalConns()))
+	gauge(c.max, float64(s.MaxConns()))
+	counter(c.acquires, float64(s.AcquireCount()))
+	counter(c.emptyAcquires, float64(s.EmptyAcquireCount()))
+	counter(c.canceled, float64(s.CanceledAcquireCount()))
+	counter(c.waitSeconds, s.AcquireDuration().Seconds())
+}
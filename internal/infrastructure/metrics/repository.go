@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+// URLRepository оборачивает next так, что каждая операция попадает в
+// repository_operation_duration_seconds с меткой backend, а сохранённые
+// ссылки — в links_created_total.
+func (m *Metrics) URLRepository(next repository.URLRepository, backend string) repository.URLRepository {
+	return &urlRepository{next: next, m: m, backend: backend}
+}
+
+// ClickRepository оборачивает next для учёта задержек операций.
+func (m *Metrics) ClickRepository(next repository.ClickRepository, backend string) repository.ClickRepository {
+	return &clickRepository{next: next, m: m, backend: backend}
+}
+
+type urlRepository struct {
+	next    repository.URLRepository
+	m       *Metrics
+	backend string
+}
+
+func (r *urlRepository) Create(ctx context.Context, url *model.URL) error {
+	defer r.m.observeRepo(r.backend, "create", time.Now())
+	err := r.next.Create(ctx, url)
+	if err == nil {
+		r.m.linksCreated.Inc()
+	}
+	return err
+}
+
+func (r *urlRepository) CreateBatch(ctx context.Context, urls []*model.URL) ([]error, error) {
+	defer r.m.observeRepo(r.backend, "create_batch", time.Now())
+	r.m.batchSize.Observe(float64(len(urls)))
+
+	itemErrs, err := r.next.CreateBatch(ctx, urls)
+	if err == nil {
+		for _, e := range itemErrs {
+			if e == nil {
+				r.m.linksCreated.Inc()
+			}
+		}
+	}
+	return itemErrs, err
+}
+
+func (r *urlRepository) FindByHash(ctx context.Context, hash string) (*model.URL, error) {
+	defer r.m.observeRepo(r.backend, "find_by_hash", time.Now())
+	return r.next.FindByHash(ctx, hash)
+}
+
+func (r *urlRepository) FindByOriginalURL(ctx context.Context, originalURL string) (*model.URL, error) {
+	defer r.m.observeRepo(r.backend, "find_by_original_url", time.Now())
+	return r.next.FindByOriginalURL(ctx, originalURL)
+}
+
+func (r *urlRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*model.URL, error) {
+	defer r.m.observeRepo(r.backend, "find_by_user_id", time.Now())
+	return r.next.FindByUserID(ctx, userID)
+}
+
+func (r *urlRepository) FindPage(ctx context.Context, q model.URLPageQuery) ([]*model.URL, error) {
+	defer r.m.observeRepo(r.backend, "find_page", time.Now())
+	return r.next.FindPage(ctx, q)
+}
+
+func (r *urlRepository) DeleteBatch(ctx context.Context, byUser map[uuid.UUID][]string) error {
+	defer r.m.observeRepo(r.backend, "delete_batch", time.Now())
+	return r.next.DeleteBatch(ctx, byUser)
+}
+
+func (r *urlRepository) Update(ctx context.Context, url *model.URL, rev *model.URLRevision) error {
+	defer r.m.observeRepo(r.backend, "update", time.Now())
+	return r.next.Update(ctx, url, rev)
+}
+
+func (r *urlRepository) FindRevisions(ctx context.Context, urlID uuid.UUID) ([]*model.URLRevision, error) {
+	defer r.m.observeRepo(r.backend, "find_revisions", time.Now())
+	return r.next.FindRevisions(ctx, urlID)
+}
+
+func (r *urlRepository) CountURLs(ctx context.Context) (int, error) {
+	defer r.m.observeRepo(r.backend, "count_urls", time.Now())
+	return r.next.CountURLs(ctx)
+}
+
+func (r *urlRepository) CountUsers(ctx context.Context) (int, error) {
+	defer r.m.observeRepo(r.backend, "count_users", time.Now())
+	return r.next.CountUsers(ctx)
+}
+
+func (r *urlRepository) RegisterClick(ctx context.Context, hash string) (bool, error) {
+	defer r.m.observeRepo(r.backend, "register_click", time.Now())
+	return r.next.RegisterClick(ctx, hash)
+}
+
+func (r *urlRepository) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	defer r.m.observeRepo(r.backend, "purge_expired", time.Now())
+	return r.next.PurgeExpired(ctx, now)
+}
+
+type clickRepository struct {
+	next    repository.ClickRepository
+	m       *Metrics
+	backend string
+}
+
+func (r *clickRepository) SaveBatch(ctx context.Context, clicks []*model.Click) error {
+	defer r.m.observeRepo(r.backend, "save_clicks", time.Now())
+	return r.next.SaveBatch(ctx, clicks)
+}
+
+func (r *clickRepository) Stats(ctx context.Context, q model.ClickStatsQuery) (*model.ClickStats, error) {
+	defer r.m.observeRepo(r.backend, "click_stats", time.Now())
+	return r.next.Stats(ctx, q)
+}
+
+func (r *clickRepository) CountByURL(ctx context.Context, urlIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	defer r.m.observeRepo(r.backend, "count_clicks", time.Now())
+	return r.next.CountByURL(ctx, urlIDs)
+}
@@ -0,0 +1,30 @@
+package click
+
+import (
+	"context"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/google/uuid"
+)
+
+type FileRepository struct {
+	storage *storage.FileStorage
+}
+
+func NewFileClickRepository(s *storage.FileStorage) repository.ClickRepository {
+	return &FileRepository{storage: s}
+}
+
+func (r *FileRepository) SaveBatch(_ context.Context, clicks []*model.Click) error {
+	return r.storage.AppendClicks(clicks)
+}
+
+func (r *FileRepository) Stats(_ context.Context, q model.ClickStatsQuery) (*model.ClickStats, error) {
+	return aggregate(r.storage.Clicks(q.URLID), q), nil
+}
+
+func (r *FileRepository) CountByURL(_ context.Context, urlIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	return r.storage.ClickCounts(urlIDs), nil
+}
@@ -0,0 +1,47 @@
+package click
+
+import (
+	"sort"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+)
+
+// aggregate считает статистику по переходам одной ссылки в памяти.
+func aggregate(clicks []*model.Click, q model.ClickStatsQuery) *model.ClickStats {
+	stats := &model.ClickStats{}
+	visitors := make(map[[2]string]struct{})
+	daily := make(map[time.Time]int)
+	hourly := make(map[time.Time]int)
+
+	for _, c := range clicks {
+		if c.URLID != q.URLID {
+			continue
+		}
+
+		stats.Total++
+		visitors[[2]string{c.IP, c.UserAgent}] = struct{}{}
+
+		at := c.OccurredAt.UTC()
+		if !at.Before(q.DailyFrom) {
+			daily[at.Truncate(24*time.Hour)]++
+		}
+		if !at.Before(q.HourlyFrom) {
+			hourly[at.Truncate(time.Hour)]++
+		}
+	}
+
+	stats.UniqueVisitors = len(visitors)
+	stats.Daily = toPoints(daily)
+	stats.Hourly = toPoints(hourly)
+	return stats
+}
+
+func toPoints(buckets map[time.Time]int) []model.ClickPoint {
+	points := make([]model.ClickPoint, 0, len(buckets))
+	for t, n := range buckets {
+		points = append(points, model.ClickPoint{Time: t, Clicks: n})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	return points
+}
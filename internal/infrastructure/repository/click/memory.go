@@ -0,0 +1,33 @@
+package click
+
+import (
+	"context"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/google/uuid"
+)
+
+type inMemoryRepository struct {
+	storage *storage.InMemoryStorage
+}
+
+var _ repository.ClickRepository = (*inMemoryRepository)(nil)
+
+func NewInMemoryClickRepository(s *storage.InMemoryStorage) repository.ClickRepository {
+	return &inMemoryRepository{storage: s}
+}
+
+func (r *inMemoryRepository) SaveBatch(_ context.Context, clicks []*model.Click) error {
+	r.storage.AppendClicks(clicks)
+	return nil
+}
+
+func (r *inMemoryRepository) Stats(_ context.Context, q model.ClickStatsQuery) (*model.ClickStats, error) {
+	return aggregate(r.storage.Clicks(q.URLID), q), nil
+}
+
+func (r *inMemoryRepository) CountByURL(_ context.Context, urlIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	return r.storage.ClickCounts(urlIDs), nil
+}
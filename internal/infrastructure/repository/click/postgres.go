@@ -0,0 +1,134 @@
+package click
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresClickRepository(pool *pgxpool.Pool) *PostgresRepository {
+	return &PostgresRepository{pool: pool}
+}
+
+// SaveBatch сохраняет переходы одним запросом. Переходы по ссылкам, которые
+// успели удалить, отбрасываются, а не обрывают сохранение всей пачки.
+func (r *PostgresRepository) SaveBatch(ctx context.Context, clicks []*model.Click) error {
+	var (
+		urlIDs     = make([]uuid.UUID, 0, len(clicks))
+		occurred   = make([]time.Time, 0, len(clicks))
+		referrers  = make([]string, 0, len(clicks))
+		userAgents = make([]string, 0, len(clicks))
+		ips        = make([]string, 0, len(clicks))
+	)
+	for _, c := range clicks {
+		urlIDs = append(urlIDs, c.URLID)
+		occurred = append(occurred, c.OccurredAt)
+		referrers = append(referrers, c.Referrer)
+		userAgents = append(userAgents, c.UserAgent)
+		ips = append(ips, c.IP)
+	}
+
+	_, err := r.pool.Exec(ctx,
+		`insert into clicks (url_id, occurred_at, referrer, user_agent, ip)
+         select c.url_id, c.occurred_at, c.referrer, c.user_agent, c.ip
+         from unnest($1::uuid[], $2::timestamptz[], $3::text[], $4::text[], $5::text[])
+             as c (url_id, occurred_at, referrer, user_agent, ip)
+         where exists (select 1 from urls where urls.id = c.url_id)`,
+		urlIDs, occurred, referrers, userAgents, ips,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save clicks: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) Stats(ctx context.Context, q model.ClickStatsQuery) (*model.ClickStats, error) {
+	stats := &model.ClickStats{}
+
+	err := r.pool.QueryRow(ctx,
+		`select count(*), count(distinct (ip, user_agent))
+         from clicks
+         where url_id = $1`,
+		q.URLID,
+	).Scan(&stats.Total, &stats.UniqueVisitors)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.Daily, err = r.series(ctx, "day", q); err != nil {
+		return nil, err
+	}
+	if stats.Hourly, err = r.series(ctx, "hour", q); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (r *PostgresRepository) CountByURL(ctx context.Context, urlIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	rows, err := r.pool.Query(ctx,
+		"select url_id, count(*) from clicks where url_id = any($1) group by url_id",
+		urlIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[uuid.UUID]int, len(urlIDs))
+	for rows.Next() {
+		var (
+			id uuid.UUID
+			n  int
+		)
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		counts[id] = n
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *PostgresRepository) series(ctx context.Context, unit string, q model.ClickStatsQuery) ([]model.ClickPoint, error) {
+	from := q.DailyFrom
+	if unit == "hour" {
+		from = q.HourlyFrom
+	}
+
+	rows, err := r.pool.Query(ctx,
+		`select date_trunc($1, occurred_at, 'UTC') as bucket, count(*)
+         from clicks
+         where url_id = $2 and occurred_at >= $3
+         group by bucket
+         order by bucket`,
+		unit,
+		q.URLID,
+		from,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := make([]model.ClickPoint, 0)
+	for rows.Next() {
+		var p model.ClickPoint
+		if err := rows.Scan(&p.Time, &p.Clicks); err != nil {
+			return nil, err
+		}
+		p.Time = p.Time.UTC()
+		points = append(points, p)
+	}
+
+	return points, rows.Err()
+}
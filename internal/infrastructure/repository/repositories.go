@@ -2,30 +2,67 @@ package repository
 
 import (
 	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/infrastructure/metrics"
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository/click"
 	"github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
 	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/amberdance/url-shortener/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Provider interface {
 	URLRepository() repository.URLRepository
+	ClickRepository() repository.ClickRepository
 }
 
 type repositories struct {
-	urlRepo repository.URLRepository
+	urlRepo   repository.URLRepository
+	clickRepo repository.ClickRepository
 }
 
 func (r *repositories) URLRepository() repository.URLRepository {
 	return r.urlRepo
 }
 
+func (r *repositories) ClickRepository() repository.ClickRepository {
+	return r.clickRepo
+}
+
 func NewRepositories(s *storage.PostgresStorage) Provider {
-	return &repositories{urlRepo: url.NewPostgresURLRepository(s.Pool())}
+	return &repositories{
+		urlRepo:   url.NewPostgresURLRepository(s.Pool()),
+		clickRepo: click.NewPostgresClickRepository(s.Pool()),
+	}
 }
 
 func NewFileRepositories(s *storage.FileStorage) Provider {
-	return &repositories{urlRepo: url.NewFileURLRepository(s)}
+	return &repositories{
+		urlRepo:   url.NewFileURLRepository(s),
+		clickRepo: click.NewFileClickRepository(s),
+	}
 }
 
 func NewMemoryRepositories(s *storage.InMemoryStorage) Provider {
-	return &repositories{urlRepo: url.NewInMemoryURLRepository(s)}
+	return &repositories{
+		urlRepo:   url.NewInMemoryURLRepository(s),
+		clickRepo: click.NewInMemoryClickRepository(s),
+	}
+}
+
+// NewInstrumentedRepositories оборачивает репозитории p сбором метрик и
+// трассировкой; backend попадает в метки рядов и атрибуты span'ов.
+func NewInstrumentedRepositories(p Provider, backend string, m *metrics.Metrics, tp trace.TracerProvider) Provider {
+	return &repositories{
+		urlRepo:   m.URLRepository(tracing.URLRepository(p.URLRepository(), backend, tp), backend),
+		clickRepo: m.ClickRepository(tracing.ClickRepository(p.ClickRepository(), backend, tp), backend),
+	}
+}
+
+// ReplaceURLRepository подменяет URL-репозиторий p обёрткой u, например
+// кэшем или рассылкой уведомлений.
+func ReplaceURLRepository(p Provider, u repository.URLRepository) Provider {
+	return &repositories{
+		urlRepo:   u,
+		clickRepo: p.ClickRepository(),
+	}
 }
@@ -0,0 +1,167 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+type CacheOptions struct {
+	// Size — сколько ссылок и сколько отсутствующих кодов хранится в кэше.
+	Size int
+	// TTL — сколько живёт найденная ссылка.
+	TTL time.Duration
+	// NegativeTTL — сколько помнится, что кода нет.
+	NegativeTTL time.Duration
+}
+
+type CacheStats struct {
+	Hits         uint64
+	NegativeHits uint64
+	Misses       uint64
+	Entries      int
+}
+
+// CachedURLRepository — read-through кэш FindByHash поверх next.
+// Отсутствующие коды тоже кэшируются, чтобы перебор несуществующих ссылок
+// не доходил до базы. Изменения через этот же репозиторий сбрасывают
+// затронутые записи; изменения из других экземпляров сервиса нужно
+// передавать в Invalidate.
+type CachedURLRepository struct {
+	repository.URLRepository
+
+	found   *lru[string, *model.URL]
+	missing *lru[string, error]
+
+	// generation растёт при каждой инвалидации. Ответ next, полученный до
+	// инвалидации, не должен остаться в кэше, иначе созданная в этот момент
+	// ссылка могла бы остаться «несуществующей» до истечения NegativeTTL.
+	// Инвалидация может прийти и между проверкой generation и записью в кэш,
+	// поэтому после записи generation проверяется ещё раз, см. FindByHash.
+	generation atomic.Uint64
+
+	hits         atomic.Uint64
+	negativeHits atomic.Uint64
+	misses       atomic.Uint64
+}
+
+var _ repository.URLRepository = (*CachedURLRepository)(nil)
+
+func NewCachedURLRepository(next repository.URLRepository, o CacheOptions) *CachedURLRepository {
+	return &CachedURLRepository{
+		URLRepository: next,
+		found:         newLRU[string, *model.URL](o.Size, o.TTL, time.Now),
+		missing:       newLRU[string, error](o.Size, o.NegativeTTL, time.Now),
+	}
+}
+
+func (r *CachedURLRepository) FindByHash(ctx context.Context, hash string) (*model.URL, error) {
+	if m, ok := r.found.get(hash); ok {
+		r.hits.Add(1)
+		cp := *m
+		return &cp, nil
+	}
+	if err, ok := r.missing.get(hash); ok {
+		r.negativeHits.Add(1)
+		return nil, err
+	}
+	r.misses.Add(1)
+
+	gen := r.generation.Load()
+	m, err := r.URLRepository.FindByHash(ctx, hash)
+	if r.generation.Load() != gen {
+		return m, err
+	}
+
+	switch {
+	case err == nil:
+		cp := *m
+		r.found.put(hash, &cp)
+	case isNotFound(err):
+		r.missing.put(hash, err)
+	default:
+		return m, err
+	}
+
+	// Invalidate увеличивает generation до удаления записей. Если она
+	// успела сделать это до нашей записи, удаляем запись сами; если нет —
+	// запись удалит она.
+	if r.generation.Load() != gen {
+		r.found.remove(hash)
+		r.missing.remove(hash)
+	}
+	return m, err
+}
+
+func (r *CachedURLRepository) Create(ctx context.Context, url *model.URL) error {
+	err := r.URLRepository.Create(ctx, url)
+	r.Invalidate(url.Hash)
+	return err
+}
+
+func (r *CachedURLRepository) CreateBatch(ctx context.Context, urls []*model.URL) ([]error, error) {
+	itemErrs, err := r.URLRepository.CreateBatch(ctx, urls)
+	for _, u := range urls {
+		r.Invalidate(u.Hash)
+	}
+	return itemErrs, err
+}
+
+func (r *CachedURLRepository) DeleteBatch(ctx context.Context, byUser map[uuid.UUID][]string) error {
+	err := r.URLRepository.DeleteBatch(ctx, byUser)
+	for _, hashes := range byUser {
+		for _, hash := range hashes {
+			r.Invalidate(hash)
+		}
+	}
+	return err
+}
+
+func (r *CachedURLRepository) Update(ctx context.Context, url *model.URL, rev *model.URLRevision) error {
+	err := r.URLRepository.Update(ctx, url, rev)
+	r.Invalidate(url.Hash)
+	return err
+}
+
+// PurgeExpired не сообщает, какие ссылки удалены, поэтому после
+// непустой очистки кэш сбрасывается целиком.
+func (r *CachedURLRepository) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	n, err := r.URLRepository.PurgeExpired(ctx, now)
+	if n > 0 {
+		r.InvalidateAll()
+	}
+	return n, err
+}
+
+// Invalidate убирает из кэша всё, что известно о коде hash.
+func (r *CachedURLRepository) Invalidate(hash string) {
+	r.generation.Add(1)
+	r.found.remove(hash)
+	r.missing.remove(hash)
+}
+
+func (r *CachedURLRepository) InvalidateAll() {
+	r.generation.Add(1)
+	r.found.clear()
+	r.missing.clear()
+}
+
+func (r *CachedURLRepository) Stats() CacheStats {
+	return CacheStats{
+		Hits:         r.hits.Load(),
+		NegativeHits: r.negativeHits.Load(),
+		Misses:       r.misses.Load(),
+		Entries:      r.found.len() + r.missing.len(),
+	}
+}
+
+func isNotFound(err error) bool {
+	var notFound errs.NotFoundError
+	return errors.As(err, &notFound)
+}
@@ -0,0 +1,147 @@
+package url
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRepository считает обращения к FindByHash и, как PostgreSQL,
+// отвечает на отсутствующий код ошибкой «не найдено».
+type countingRepository struct {
+	repository.URLRepository
+	finds int
+}
+
+func (r *countingRepository) FindByHash(ctx context.Context, hash string) (*model.URL, error) {
+	r.finds++
+	m, err := r.URLRepository.FindByHash(ctx, hash)
+	if err != nil {
+		return nil, errs.NotFoundError("url not found")
+	}
+	return m, nil
+}
+
+func newCachedRepo() (*CachedURLRepository, *countingRepository) {
+	next := &countingRepository{URLRepository: NewInMemoryURLRepository(storage.NewInMemoryStorage())}
+	return NewCachedURLRepository(next, CacheOptions{Size: 2, TTL: time.Minute, NegativeTTL: time.Minute}), next
+}
+
+func TestCachedURLRepository_ReadThrough(t *testing.T) {
+	ctx := context.Background()
+	repo, next := newCachedRepo()
+	require.NoError(t, repo.Create(ctx, newURL(t, "https://a.example", "a", nil)))
+
+	for range 3 {
+		m, err := repo.FindByHash(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "https://a.example", m.OriginalURL)
+	}
+
+	assert.Equal(t, 1, next.finds)
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Entries: 1}, repo.Stats())
+}
+
+func TestCachedURLRepository_NegativeCacheClearedOnCreate(t *testing.T) {
+	ctx := context.Background()
+	repo, next := newCachedRepo()
+
+	for range 3 {
+		_, err := repo.FindByHash(ctx, "new")
+		var notFound errs.NotFoundError
+		require.ErrorAs(t, err, &notFound)
+	}
+	assert.Equal(t, 1, next.finds)
+	assert.Equal(t, uint64(2), repo.Stats().NegativeHits)
+
+	require.NoError(t, repo.Create(ctx, newURL(t, "https://new.example", "new", nil)))
+	m, err := repo.FindByHash(ctx, "new")
+	require.NoError(t, err)
+	assert.Equal(t, "https://new.example", m.OriginalURL)
+}
+
+func TestCachedURLRepository_InvalidatedOnDelete(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newCachedRepo()
+	userID := uuid.New()
+	require.NoError(t, repo.Create(ctx, newURL(t, "https://a.example", "a", &userID)))
+
+	m, err := repo.FindByHash(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, m.IsDeleted)
+
+	require.NoError(t, repo.DeleteBatch(ctx, map[uuid.UUID][]string{userID: []string{"a"}}))
+	m, err = repo.FindByHash(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, m.IsDeleted)
+}
+
+func TestCachedURLRepository_InvalidationDuringLookupIsNotLost(t *testing.T) {
+	ctx := context.Background()
+	repo, next := newCachedRepo()
+	require.NoError(t, repo.Create(ctx, newURL(t, "https://a.example", "a", nil)))
+
+	// Инвалидация приходит, пока запрос к next ещё выполняется.
+	inner := next.URLRepository
+	next.URLRepository = invalidatingRepository{URLRepository: inner, invalidate: func() { repo.Invalidate("a") }}
+	_, err := repo.FindByHash(ctx, "a")
+	require.NoError(t, err)
+	next.URLRepository = inner
+
+	_, err = repo.FindByHash(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 2, next.finds, "устаревший ответ не попал в кэш")
+}
+
+type invalidatingRepository struct {
+	repository.URLRepository
+	invalidate func()
+}
+
+func (r invalidatingRepository) FindByHash(ctx context.Context, hash string) (*model.URL, error) {
+	defer r.invalidate()
+	return r.URLRepository.FindByHash(ctx, hash)
+}
+
+func TestCachedURLRepository_ReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newCachedRepo()
+	require.NoError(t, repo.Create(ctx, newURL(t, "https://a.example", "a", nil)))
+
+	m, err := repo.FindByHash(ctx, "a")
+	require.NoError(t, err)
+	m.OriginalURL = "https://changed.example"
+
+	m, err = repo.FindByHash(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.example", m.OriginalURL)
+}
+
+func TestLRU_EvictsAndExpires(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newLRU[string, int](2, time.Minute, func() time.Time { return now })
+
+	c.put("a", 1)
+	c.put("b", 2)
+	_, _ = c.get("a")
+	c.put("c", 3)
+
+	_, ok := c.get("b")
+	assert.False(t, ok, "давно не использованная запись вытеснена")
+	v, ok := c.get("a")
+	require.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	now = now.Add(time.Minute)
+	_, ok = c.get("a")
+	assert.False(t, ok, "запись истекла")
+	assert.Equal(t, 1, c.len())
+}
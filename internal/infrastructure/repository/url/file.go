@@ -2,12 +2,14 @@ package url
 
 import (
 	"context"
-	"errors"
+	"slices"
+	"time"
 
 	"github.com/amberdance/url-shortener/internal/domain/errs"
 	"github.com/amberdance/url-shortener/internal/domain/model"
 	"github.com/amberdance/url-shortener/internal/domain/repository"
 	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/google/uuid"
 )
 
 type FileRepository struct {
@@ -20,22 +22,18 @@ func NewFileURLRepository(s *storage.FileStorage) repository.URLRepository {
 	}
 }
 
-func (r *FileRepository) Create(ctx context.Context, u *model.URL) error {
-	if existing, _ := r.FindByOriginalURL(ctx, u.OriginalURL); existing != nil {
-		return errs.DuplicateEntryError("url already exists")
-	}
-
+func (r *FileRepository) Create(_ context.Context, u *model.URL) error {
 	return r.storage.Put(u)
 }
 
-func (r *FileRepository) CreateBatch(_ context.Context, urls []*model.URL) error {
+func (r *FileRepository) CreateBatch(_ context.Context, urls []*model.URL) ([]error, error) {
 	return r.storage.PutBatch(urls)
 }
 
 func (r *FileRepository) FindByHash(_ context.Context, hash string) (*model.URL, error) {
 	u, ok := r.storage.GetByHash(hash)
 	if !ok {
-		return nil, errors.New("not found")
+		return nil, errs.NotFoundError("url not found")
 	}
 	return u, nil
 }
@@ -43,7 +41,49 @@ func (r *FileRepository) FindByHash(_ context.Context, hash string) (*model.URL,
 func (r *FileRepository) FindByOriginalURL(_ context.Context, originalURL string) (*model.URL, error) {
 	u, ok := r.storage.GetByOriginalURL(originalURL)
 	if !ok {
-		return nil, errs.NotFoundError("url not found")
+		return nil, nil
 	}
 	return u, nil
 }
+
+func (r *FileRepository) FindByUserID(_ context.Context, userID uuid.UUID) ([]*model.URL, error) {
+	urls := r.storage.GetByUserID(userID)
+	sortByCreatedAt(urls)
+	return urls, nil
+}
+
+func (r *FileRepository) FindPage(_ context.Context, q model.URLPageQuery) ([]*model.URL, error) {
+	return r.storage.Page(q), nil
+}
+
+func (r *FileRepository) DeleteBatch(_ context.Context, byUser map[uuid.UUID][]string) error {
+	return r.storage.MarkDeleted(byUser)
+}
+
+func (r *FileRepository) Update(_ context.Context, u *model.URL, rev *model.URLRevision) error {
+	return r.storage.Revise(u, rev)
+}
+
+func (r *FileRepository) FindRevisions(_ context.Context, urlID uuid.UUID) ([]*model.URLRevision, error) {
+	revs := r.storage.Revisions(urlID)
+	slices.Reverse(revs)
+	return revs, nil
+}
+
+func (r *FileRepository) CountURLs(_ context.Context) (int, error) {
+	urls, _ := r.storage.Stats()
+	return urls, nil
+}
+
+func (r *FileRepository) CountUsers(_ context.Context) (int, error) {
+	_, users := r.storage.Stats()
+	return users, nil
+}
+
+func (r *FileRepository) RegisterClick(_ context.Context, hash string) (bool, error) {
+	return r.storage.RegisterClick(hash)
+}
+
+func (r *FileRepository) PurgeExpired(_ context.Context, now time.Time) (int, error) {
+	return r.storage.Remove(func(u *model.URL) bool { return u.IsExpired(now) })
+}
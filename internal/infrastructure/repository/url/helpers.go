@@ -0,0 +1,13 @@
+package url
+
+import (
+	"sort"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+)
+
+func sortByCreatedAt(urls []*model.URL) {
+	sort.Slice(urls, func(i, j int) bool {
+		return urls[i].CreatedAt.Before(urls[j].CreatedAt)
+	})
+}
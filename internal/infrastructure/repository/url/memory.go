@@ -2,13 +2,14 @@ package url
 
 import (
 	"context"
-	"errors"
-	"fmt"
+	"slices"
+	"time"
 
 	"github.com/amberdance/url-shortener/internal/domain/errs"
 	"github.com/amberdance/url-shortener/internal/domain/model"
 	"github.com/amberdance/url-shortener/internal/domain/repository"
 	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/google/uuid"
 )
 
 type inMemoryRepository struct {
@@ -23,54 +24,95 @@ func NewInMemoryURLRepository(s *storage.InMemoryStorage) repository.URLReposito
 	}
 }
 
-func (r *inMemoryRepository) Create(ctx context.Context, m *model.URL) error {
-	existing, _ := r.FindByOriginalURL(ctx, m.OriginalURL)
-	if existing != nil {
-		return errs.DuplicateEntryError("url already exists")
+func (r *inMemoryRepository) Create(_ context.Context, m *model.URL) error {
+	return r.storage.Put(m)
+}
+
+func (r *inMemoryRepository) CreateBatch(_ context.Context, urls []*model.URL) ([]error, error) {
+	return r.storage.PutBatch(urls), nil
+}
+
+func (r *inMemoryRepository) FindByHash(_ context.Context, hash string) (*model.URL, error) {
+	m, ok := r.storage.GetByHash(hash)
+	if !ok {
+		return nil, errs.NotFoundError("url not found")
 	}
+	return m, nil
+}
 
-	r.storage.Mu.Lock()
-	defer r.storage.Mu.Unlock()
+func (r *inMemoryRepository) FindByOriginalURL(_ context.Context, originalURL string) (*model.URL, error) {
+	m, ok := r.storage.GetByOriginalURL(originalURL)
+	if !ok {
+		return nil, nil
+	}
+	return m, nil
+}
 
-	r.storage.Data[m.ID] = m
-	return nil
+func (r *inMemoryRepository) FindByUserID(_ context.Context, userID uuid.UUID) ([]*model.URL, error) {
+	urls := r.storage.GetByUserID(userID)
+	sortByCreatedAt(urls)
+	return urls, nil
+}
+
+func (r *inMemoryRepository) FindPage(_ context.Context, q model.URLPageQuery) ([]*model.URL, error) {
+	return r.storage.Page(q), nil
 }
 
-func (r *inMemoryRepository) CreateBatch(_ context.Context, urls []*model.URL) error {
-	r.storage.Mu.Lock()
-	defer r.storage.Mu.Unlock()
-	for _, u := range urls {
-		if _, ok := r.storage.Data[u.ID]; ok {
-			return fmt.Errorf("duplicate hash: %s", u.Hash)
+func (r *inMemoryRepository) DeleteBatch(_ context.Context, byUser map[uuid.UUID][]string) error {
+	now := time.Now()
+	for userID, hashes := range byUser {
+		for _, hash := range hashes {
+			r.storage.Update(hash, func(m *model.URL) bool {
+				if m.IsDeleted || m.UserID == nil || *m.UserID != userID {
+					return false
+				}
+				m.IsDeleted = true
+				m.DeletedAt = &now
+				return true
+			})
 		}
 	}
-	for _, u := range urls {
-		r.storage.Data[u.ID] = u
-	}
+
 	return nil
 }
 
-func (r *inMemoryRepository) FindByHash(_ context.Context, url string) (*model.URL, error) {
-	r.storage.Mu.RLock()
-	defer r.storage.Mu.RUnlock()
+func (r *inMemoryRepository) Update(_ context.Context, m *model.URL, rev *model.URLRevision) error {
+	return r.storage.Revise(m, rev)
+}
 
-	for _, item := range r.storage.Data {
-		if item.Hash == url {
-			return item, nil
-		}
-	}
-	return nil, errors.New("url not found")
+func (r *inMemoryRepository) FindRevisions(_ context.Context, urlID uuid.UUID) ([]*model.URLRevision, error) {
+	revs := r.storage.Revisions(urlID)
+	slices.Reverse(revs)
+	return revs, nil
 }
 
-func (r *inMemoryRepository) FindByOriginalURL(_ context.Context, originalURL string) (*model.URL, error) {
-	r.storage.Mu.RLock()
-	defer r.storage.Mu.RUnlock()
+func (r *inMemoryRepository) CountURLs(_ context.Context) (int, error) {
+	urls, _ := r.storage.Stats()
+	return urls, nil
+}
+
+func (r *inMemoryRepository) CountUsers(_ context.Context) (int, error) {
+	_, users := r.storage.Stats()
+	return users, nil
+}
 
-	for _, m := range r.storage.Data {
-		if m.OriginalURL == originalURL {
-			return m, nil
+func (r *inMemoryRepository) RegisterClick(_ context.Context, hash string) (bool, error) {
+	registered := false
+	found := r.storage.Update(hash, func(m *model.URL) bool {
+		if m.MaxClicks != nil && m.Clicks >= *m.MaxClicks {
+			return false
 		}
+		m.Clicks++
+		registered = true
+		return true
+	})
+	if !found {
+		return false, errs.NotFoundError("url not found")
 	}
 
-	return nil, nil
+	return registered, nil
+}
+
+func (r *inMemoryRepository) PurgeExpired(_ context.Context, now time.Time) (int, error) {
+	return r.storage.Remove(func(m *model.URL) bool { return m.IsExpired(now) }), nil
 }
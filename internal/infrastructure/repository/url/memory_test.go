@@ -0,0 +1,198 @@
+package url
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newURL(t testing.TB, original, hash string, userID *uuid.UUID) *model.URL {
+	m, err := model.NewURL(original, hash, nil, userID, time.Now())
+	require.NoError(t, err)
+	return m
+}
+
+func TestRepository_ConcurrentCreateSameURL(t *testing.T) {
+	file, err := storage.NewFileStorage(filepath.Join(t.TempDir(), "db.json"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	repos := map[string]repository.URLRepository{
+		"memory": NewInMemoryURLRepository(storage.NewInMemoryStorage()),
+		"file":   NewFileURLRepository(file),
+	}
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			var created atomic.Int32
+			var wg sync.WaitGroup
+			for i := range 100 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					err := repo.Create(context.Background(), newURL(t, "https://hard2code.ru", fmt.Sprintf("h%d", i), nil))
+					if err == nil {
+						created.Add(1)
+						return
+					}
+					assert.ErrorAs(t, err, new(errs.DuplicateEntryError))
+				}()
+			}
+			wg.Wait()
+
+			assert.Equal(t, int32(1), created.Load())
+			count, _ := repo.CountURLs(context.Background())
+			assert.Equal(t, 1, count)
+
+			missing, err := repo.FindByOriginalURL(context.Background(), "https://missing.example")
+			assert.NoError(t, err)
+			assert.Nil(t, missing)
+		})
+	}
+}
+
+func TestInMemoryRepository_IndexesFollowUpdates(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	userID := uuid.New()
+
+	_, err := repo.CreateBatch(ctx, []*model.URL{
+		newURL(t, "https://a.example", "a", &userID),
+		newURL(t, "https://b.example", "b", &userID),
+	})
+	require.NoError(t, err)
+	itemErrs, err := repo.CreateBatch(ctx, []*model.URL{
+		newURL(t, "https://c.example", "a", nil),
+		newURL(t, "https://b.example", "x", nil),
+		newURL(t, "https://d.example", "d", nil),
+	})
+	require.NoError(t, err)
+	assert.ErrorAs(t, itemErrs[0], new(errs.HashConflictError))
+	assert.ErrorAs(t, itemErrs[1], new(errs.DuplicateEntryError))
+	assert.NoError(t, itemErrs[2])
+
+	require.NoError(t, repo.DeleteBatch(ctx, map[uuid.UUID][]string{userID: []string{"a"}}))
+
+	found, err := repo.FindByHash(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, found.IsDeleted)
+
+	byOriginal, err := repo.FindByOriginalURL(ctx, "https://a.example")
+	require.NoError(t, err)
+	assert.Nil(t, byOriginal, "удалённая ссылка не держит адрес")
+
+	owned, err := repo.FindByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, owned, 1)
+	assert.Equal(t, "b", owned[0].Hash)
+
+	urls, _ := repo.CountURLs(ctx)
+	users, _ := repo.CountUsers(ctx)
+	assert.Equal(t, 2, urls, "b и d из второй пачки")
+	assert.Equal(t, 1, users)
+}
+
+func TestCountUsers_SkipsOwnersWithOnlyDeletedLinks(t *testing.T) {
+	ctx := context.Background()
+	file, err := storage.NewFileStorage(filepath.Join(t.TempDir(), "db.json"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	repos := map[string]repository.URLRepository{
+		"memory": NewInMemoryURLRepository(storage.NewInMemoryStorage()),
+		"file":   NewFileURLRepository(file),
+	}
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			gone, kept := uuid.New(), uuid.New()
+			_, err := repo.CreateBatch(ctx, []*model.URL{
+				newURL(t, "https://a.example", "a", &gone),
+				newURL(t, "https://b.example", "b", &kept),
+				newURL(t, "https://c.example", "c", &kept),
+			})
+			require.NoError(t, err)
+			require.NoError(t, repo.DeleteBatch(ctx, map[uuid.UUID][]string{gone: []string{"a"}}))
+			require.NoError(t, repo.DeleteBatch(ctx, map[uuid.UUID][]string{kept: []string{"b"}}))
+
+			users, err := repo.CountUsers(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 1, users)
+		})
+	}
+}
+
+func fill(b *testing.B, repo repository.URLRepository, n int) {
+	const chunk = 10_000
+
+	now := time.Now()
+	batch := make([]*model.URL, 0, chunk)
+	for i := range n {
+		m, err := model.NewURL(fmt.Sprintf("https://example.com/%d", i), fmt.Sprintf("h%d", i), nil, nil, now)
+		require.NoError(b, err)
+		batch = append(batch, m)
+		if len(batch) == chunk || i == n-1 {
+			_, err := repo.CreateBatch(context.Background(), batch)
+			require.NoError(b, err)
+			batch = batch[:0]
+		}
+	}
+}
+
+// BenchmarkInMemoryRepository_FindByHash показывает, что время поиска при
+// редиректе не растёт с числом записей.
+func BenchmarkInMemoryRepository_FindByHash(b *testing.B) {
+	for _, n := range []int{1_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("entries=%d", n), func(b *testing.B) {
+			repo := NewInMemoryURLRepository(storage.NewInMemoryStorage())
+			fill(b, repo, n)
+			ctx := context.Background()
+
+			for i := 0; b.Loop(); i++ {
+				if _, err := repo.FindByHash(ctx, fmt.Sprintf("h%d", i%n)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkInMemoryRepository_FindByOriginalURL(b *testing.B) {
+	for _, n := range []int{1_000, 1_000_000} {
+		b.Run(fmt.Sprintf("entries=%d", n), func(b *testing.B) {
+			repo := NewInMemoryURLRepository(storage.NewInMemoryStorage())
+			fill(b, repo, n)
+			ctx := context.Background()
+
+			for i := 0; b.Loop(); i++ {
+				if m, _ := repo.FindByOriginalURL(ctx, fmt.Sprintf("https://example.com/%d", i%n)); m == nil {
+					b.Fatal("not found")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkInMemoryRepository_Create(b *testing.B) {
+	repo := NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	fill(b, repo, 1_000_000)
+	ctx := context.Background()
+	now := time.Now()
+
+	for i := 0; b.Loop(); i++ {
+		m, _ := model.NewURL(fmt.Sprintf("https://new.example.com/%d", i), fmt.Sprintf("n%d", i), nil, nil, now)
+		if err := repo.Create(ctx, m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package url
+
+import (
+	"context"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/google/uuid"
+)
+
+type LinkPublisher interface {
+	Publish(ctx context.Context, ev storage.LinkEvent) error
+}
+
+// notifyingRepository сообщает другим экземплярам сервиса о каждой
+// успешной записи, чтобы они сбросили свои кэши. Ошибка публикации не
+// отменяет запись: чужие кэши в худшем случае устареют на свой TTL.
+type notifyingRepository struct {
+	repository.URLRepository
+	publisher LinkPublisher
+	logger    shared.Logger
+}
+
+func NewNotifyingURLRepository(next repository.URLRepository, p LinkPublisher, l shared.Logger) repository.URLRepository {
+	return &notifyingRepository{URLRepository: next, publisher: p, logger: l}
+}
+
+func (r *notifyingRepository) Create(ctx context.Context, url *model.URL) error {
+	err := r.URLRepository.Create(ctx, url)
+	if err == nil {
+		r.publish(ctx, storage.LinkEvent{Op: storage.LinkUpserted, Hashes: []string{url.Hash}})
+	}
+	return err
+}
+
+func (r *notifyingRepository) CreateBatch(ctx context.Context, urls []*model.URL) ([]error, error) {
+	itemErrs, err := r.URLRepository.CreateBatch(ctx, urls)
+	if err != nil {
+		return itemErrs, err
+	}
+
+	var hashes []string
+	for i, u := range urls {
+		if itemErrs[i] == nil {
+			hashes = append(hashes, u.Hash)
+		}
+	}
+	if len(hashes) > 0 {
+		r.publish(ctx, storage.LinkEvent{Op: storage.LinkUpserted, Hashes: hashes})
+	}
+	return itemErrs, nil
+}
+
+func (r *notifyingRepository) DeleteBatch(ctx context.Context, byUser map[uuid.UUID][]string) error {
+	err := r.URLRepository.DeleteBatch(ctx, byUser)
+	var hashes []string
+	for _, hs := range byUser {
+		hashes = append(hashes, hs...)
+	}
+	if err == nil && len(hashes) > 0 {
+		r.publish(ctx, storage.LinkEvent{Op: storage.LinkDeleted, Hashes: hashes})
+	}
+	return err
+}
+
+func (r *notifyingRepository) Update(ctx context.Context, url *model.URL, rev *model.URLRevision) error {
+	err := r.URLRepository.Update(ctx, url, rev)
+	if err == nil {
+		r.publish(ctx, storage.LinkEvent{Op: storage.LinkUpserted, Hashes: []string{url.Hash}})
+	}
+	return err
+}
+
+func (r *notifyingRepository) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	n, err := r.URLRepository.PurgeExpired(ctx, now)
+	if n > 0 {
+		r.publish(ctx, storage.LinkEvent{Op: storage.LinksPurged})
+	}
+	return n, err
+}
+
+func (r *notifyingRepository) publish(ctx context.Context, ev storage.LinkEvent) {
+	if err := r.publisher.Publish(ctx, ev); err != nil {
+		r.logger.ErrorContext(ctx, "failed to publish link event", "op", ev.Op, "error", err)
+	}
+}
+
+// Apply сбрасывает записи кэша по событию другого экземпляра.
+func (r *CachedURLRepository) Apply(ev storage.LinkEvent) {
+	if ev.Op == storage.LinksPurged || ev.Op == storage.LinksResync {
+		r.InvalidateAll()
+		return
+	}
+	for _, hash := range ev.Hashes {
+		r.Invalidate(hash)
+	}
+}
@@ -0,0 +1,91 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPublisher struct {
+	events []storage.LinkEvent
+	err    error
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, ev storage.LinkEvent) error {
+	p.events = append(p.events, ev)
+	return p.err
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(_ string, _ ...any)                           {}
+func (nopLogger) Info(_ string, _ ...any)                            {}
+func (nopLogger) Error(_ string, _ ...any)                           {}
+func (nopLogger) DebugContext(_ context.Context, _ string, _ ...any) {}
+func (nopLogger) InfoContext(_ context.Context, _ string, _ ...any)  {}
+func (nopLogger) ErrorContext(_ context.Context, _ string, _ ...any) {}
+func (nopLogger) With(_ ...any) shared.Logger                        { return nopLogger{} }
+func (nopLogger) Close() error                                       { return nil }
+
+func TestNotifyingRepository_PublishesWrites(t *testing.T) {
+	ctx := context.Background()
+	pub := &recordingPublisher{}
+	repo := NewNotifyingURLRepository(NewInMemoryURLRepository(storage.NewInMemoryStorage()), pub, nopLogger{})
+	userID := uuid.New()
+
+	require.NoError(t, repo.Create(ctx, newURL(t, "https://a.example", "a", &userID)))
+	_, err := repo.CreateBatch(ctx, []*model.URL{
+		newURL(t, "https://b.example", "b", &userID),
+		newURL(t, "https://c.example", "c", &userID),
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteBatch(ctx, map[uuid.UUID][]string{userID: []string{"a", "b"}}))
+
+	require.Len(t, pub.events, 3)
+	assert.Equal(t, storage.LinkEvent{Op: storage.LinkUpserted, Hashes: []string{"a"}}, pub.events[0])
+	assert.Equal(t, storage.LinkEvent{Op: storage.LinkUpserted, Hashes: []string{"b", "c"}}, pub.events[1])
+	assert.Equal(t, storage.LinkEvent{Op: storage.LinkDeleted, Hashes: []string{"a", "b"}}, pub.events[2])
+
+	_, err = repo.PurgeExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, pub.events, 3, "nothing purged, nothing to publish")
+}
+
+func TestNotifyingRepository_PublishErrorDoesNotFailWrite(t *testing.T) {
+	pub := &recordingPublisher{err: errors.New("connection lost")}
+	repo := NewNotifyingURLRepository(NewInMemoryURLRepository(storage.NewInMemoryStorage()), pub, nopLogger{})
+
+	require.NoError(t, repo.Create(context.Background(), newURL(t, "https://a.example", "a", nil)))
+	assert.Len(t, pub.events, 1)
+}
+
+func TestCachedURLRepository_Apply(t *testing.T) {
+	ctx := context.Background()
+	repo, next := newCachedRepo()
+	require.NoError(t, next.Create(ctx, newURL(t, "https://a.example", "a", nil)))
+
+	_, err := repo.FindByHash(ctx, "a")
+	require.NoError(t, err)
+	_, _ = repo.FindByHash(ctx, "missing")
+	require.Equal(t, 2, next.finds)
+
+	// Другой экземпляр создал ссылку, которую этот кэш помнит как отсутствующую.
+	require.NoError(t, next.Create(ctx, newURL(t, "https://m.example", "missing", nil)))
+	repo.Apply(storage.LinkEvent{Op: storage.LinkUpserted, Hashes: []string{"missing"}})
+	_, err = repo.FindByHash(ctx, "missing")
+	require.NoError(t, err)
+	assert.Equal(t, 3, next.finds)
+
+	repo.Apply(storage.LinkEvent{Op: storage.LinksResync})
+	_, err = repo.FindByHash(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 4, next.finds)
+}
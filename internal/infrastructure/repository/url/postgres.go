@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/amberdance/url-shortener/internal/domain/errs"
 	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -22,93 +25,438 @@ func NewPostgresURLRepository(pool *pgxpool.Pool) *PostgresRepository {
 
 func (r *PostgresRepository) Create(ctx context.Context, m *model.URL) error {
 	_, err := r.pool.Exec(ctx,
-		"insert into urls (id, created_at, hash, original_url, correlation_id) values ($1, $2, $3, $4, $5)",
+		"insert into urls (id, created_at, hash, original_url, correlation_id, user_id, expires_at, max_clicks, redirect_code, noindex) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)",
 		m.ID,
 		m.CreatedAt,
 		m.Hash,
 		m.OriginalURL,
 		m.CorrelationID,
+		m.UserID,
+		m.ExpiresAt,
+		m.MaxClicks,
+		m.RedirectCode,
+		m.NoIndex,
 	)
 
-	var pgErr *pgconn.PgError
-	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-		return errs.DuplicateEntryError(pgErr.Message)
+	if dupErr := uniqueViolation(err); dupErr != nil {
+		return dupErr
 	}
 
 	return err
 }
 
-func (r *PostgresRepository) CreateBatch(ctx context.Context, urls []*model.URL) error {
-	tx, err := r.pool.Begin(ctx)
+// copyThreshold — с какого размера пачка загружается через COPY, а не
+// отдельными insert.
+const copyThreshold = 100
+
+var batchColumns = []string{"id", "hash", "original_url", "created_at", "correlation_id", "user_id", "expires_at", "max_clicks", "redirect_code", "noindex"}
+
+// CreateBatch сохраняет пачку за один обмен с базой: небольшие пачки —
+// пакетом insert, крупные — через COPY во временную таблицу. Конфликтующие
+// строки пропускаются через on conflict do nothing, а причина конфликта
+// уточняется одним дополнительным запросом.
+func (r *PostgresRepository) CreateBatch(ctx context.Context, urls []*model.URL) ([]error, error) {
+	var (
+		skipped []int
+		err     error
+	)
+	if len(urls) >= copyThreshold {
+		skipped, err = r.copyBatch(ctx, urls)
+	} else {
+		skipped, err = r.insertBatch(ctx, urls)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to begin tx: %w", err)
+		return nil, err
 	}
 
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback(ctx)
-		}
-	}()
+	results := make([]error, len(urls))
+	if len(skipped) == 0 {
+		return results, nil
+	}
+
+	if err := r.explainConflicts(ctx, urls, skipped, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
 
+// insertBatch возвращает индексы пропущенных строк.
+func (r *PostgresRepository) insertBatch(ctx context.Context, urls []*model.URL) ([]int, error) {
 	batch := &pgx.Batch{}
-	sql := `insert into urls (id, hash, original_url, created_at, correlation_id) values ($1, $2, $3, $4, $5)`
+	sql := `insert into urls (id, hash, original_url, created_at, correlation_id, user_id, expires_at, max_clicks, redirect_code, noindex)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		on conflict do nothing`
 
 	for _, u := range urls {
-		batch.Queue(sql, u.ID, u.Hash, u.OriginalURL, u.CreatedAt, u.CorrelationID)
+		batch.Queue(sql, u.ID, u.Hash, u.OriginalURL, u.CreatedAt, u.CorrelationID, u.UserID, u.ExpiresAt, u.MaxClicks, u.RedirectCode, u.NoIndex)
 	}
 
-	br := tx.SendBatch(ctx, batch)
+	br := r.pool.SendBatch(ctx, batch)
 
-	for i := 0; i < len(urls); i++ {
-		_, execErr := br.Exec()
-		if execErr != nil {
+	var skipped []int
+	for i := range urls {
+		tag, err := br.Exec()
+		if err != nil {
 			br.Close()
-			return fmt.Errorf("batch insert failed: %w", execErr)
+			return nil, fmt.Errorf("batch insert failed: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			skipped = append(skipped, i)
 		}
 	}
 
-	if closeErr := br.Close(); closeErr != nil {
-		return fmt.Errorf("batch close failed: %w", closeErr)
+	if err := br.Close(); err != nil {
+		return nil, fmt.Errorf("batch close failed: %w", err)
+	}
+	return skipped, nil
+}
+
+// copyBatch загружает строки в временную таблицу через COPY и переносит в
+// urls те, что не конфликтуют. Возвращает индексы пропущенных строк.
+func (r *PostgresRepository) copyBatch(ctx context.Context, urls []*model.URL) ([]int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err = tx.Exec(ctx, "create temp table urls_import (like urls including defaults) on commit drop"); err != nil {
+		return nil, fmt.Errorf("failed to create staging table: %w", err)
+	}
+
+	rows := make([][]any, 0, len(urls))
+	for _, u := range urls {
+		rows = append(rows, []any{u.ID, u.Hash, u.OriginalURL, u.CreatedAt, u.CorrelationID, u.UserID, u.ExpiresAt, u.MaxClicks, u.RedirectCode, u.NoIndex})
+	}
+	if _, err = tx.CopyFrom(ctx, pgx.Identifier{"urls_import"}, batchColumns, pgx.CopyFromRows(rows)); err != nil {
+		return nil, fmt.Errorf("copy failed: %w", err)
+	}
+
+	cols := strings.Join(batchColumns, ", ")
+	inserted, err := tx.Query(ctx,
+		"insert into urls ("+cols+") select "+cols+" from urls_import on conflict do nothing returning id",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("batch insert failed: %w", err)
+	}
+	ids, err := pgx.CollectRows(inserted, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("batch insert failed: %w", err)
 	}
 
 	if err = tx.Commit(ctx); err != nil {
-		return fmt.Errorf("commit failed: %w", err)
+		return nil, fmt.Errorf("commit failed: %w", err)
 	}
 
+	saved := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		saved[id] = struct{}{}
+	}
+
+	var skipped []int
+	for i, u := range urls {
+		if _, ok := saved[u.ID]; !ok {
+			skipped = append(skipped, i)
+		}
+	}
+	return skipped, nil
+}
+
+// explainConflicts заполняет results для пропущенных строк.
+func (r *PostgresRepository) explainConflicts(ctx context.Context, urls []*model.URL, skipped []int, results []error) error {
+	hashes := make([]string, 0, len(skipped))
+	originals := make([]string, 0, len(skipped))
+	for _, i := range skipped {
+		hashes = append(hashes, urls[i].Hash)
+		originals = append(originals, urls[i].OriginalURL)
+	}
+
+	rows, err := r.pool.Query(ctx,
+		"select hash, original_url, is_deleted from urls where hash = any($1) or (original_url = any($2) and not is_deleted)",
+		hashes, originals,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to resolve batch conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	byHash := make(map[string]struct{})
+	byOriginal := make(map[string]struct{})
+	for rows.Next() {
+		var (
+			hash, original string
+			deleted        bool
+		)
+		if err := rows.Scan(&hash, &original, &deleted); err != nil {
+			return err
+		}
+		byHash[hash] = struct{}{}
+		if !deleted {
+			byOriginal[original] = struct{}{}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, i := range skipped {
+		u := urls[i]
+		if _, ok := byOriginal[u.OriginalURL]; ok {
+			results[i] = errs.DuplicateEntryError(fmt.Sprintf("duplicate url: %s", u.OriginalURL))
+		} else if _, ok := byHash[u.Hash]; ok {
+			results[i] = errs.HashConflictError(fmt.Sprintf("duplicate hash: %s", u.Hash))
+		} else {
+			// Остаётся уникальный correlation_id.
+			results[i] = errs.DuplicateEntryError("duplicate correlation id")
+		}
+	}
 	return nil
 }
 
 func (r *PostgresRepository) FindByHash(ctx context.Context, hash string) (*model.URL, error) {
 	row := r.pool.QueryRow(ctx,
-		"select id, created_at, updated_at, hash, original_url, correlation_id from urls where hash = $1",
+		"select "+urlColumns+" from urls where hash = $1",
 		hash,
 	)
 
-	var u model.URL
-	err := row.Scan(
-		&u.ID,
-		&u.CreatedAt,
-		&u.UpdatedAt,
-		&u.Hash,
-		&u.OriginalURL,
-		&u.CorrelationID,
-	)
-	if err != nil {
-		return nil, err
+	m, err := scanURL(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errs.NotFoundError("url not found")
 	}
-
-	return &u, nil
+	return m, err
 }
 
 func (r *PostgresRepository) FindByOriginalURL(ctx context.Context, original string) (*model.URL, error) {
 	row := r.pool.QueryRow(ctx,
-		`select id, hash, original_url, created_at, updated_at, correlation_id 
+		`select `+urlColumns+`
          from urls 
-         where original_url = $1 
+         where original_url = $1 and not is_deleted
          limit 1`,
 		original,
 	)
 
+	m, err := scanURL(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (r *PostgresRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*model.URL, error) {
+	rows, err := r.pool.Query(ctx,
+		`select `+urlColumns+`
+         from urls
+         where user_id = $1 and not is_deleted
+         order by created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []*model.URL
+	for rows.Next() {
+		m, err := scanURL(rows)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, m)
+	}
+
+	return urls, rows.Err()
+}
+
+// FindPage выбирает страницу по первичному ключу, поэтому стоимость
+// запроса не зависит от того, как далеко продвинулся курсор.
+func (r *PostgresRepository) FindPage(ctx context.Context, q model.URLPageQuery) ([]*model.URL, error) {
+	rows, err := r.pool.Query(ctx,
+		`select `+urlColumns+`
+         from urls
+         where id > $1 and not is_deleted and ($2::uuid is null or user_id = $2)
+         order by id
+         limit $3`,
+		q.After, q.UserID, q.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []*model.URL
+	for rows.Next() {
+		m, err := scanURL(rows)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, m)
+	}
+
+	return urls, rows.Err()
+}
+
+func (r *PostgresRepository) DeleteBatch(ctx context.Context, byUser map[uuid.UUID][]string) error {
+	var hashes []string
+	var userIDs []uuid.UUID
+	for userID, hs := range byUser {
+		for _, hash := range hs {
+			hashes = append(hashes, hash)
+			userIDs = append(userIDs, userID)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	_, err := r.pool.Exec(ctx,
+		`update urls u
+         set is_deleted = true, deleted_at = now()
+         from unnest($1::text[], $2::uuid[]) as d(hash, user_id)
+         where u.hash = d.hash and u.user_id = d.user_id and not u.is_deleted`,
+		hashes,
+		userIDs,
+	)
+
+	return err
+}
+
+// Update меняет ссылку и записывает ревизию в одной транзакции.
+func (r *PostgresRepository) Update(ctx context.Context, m *model.URL, rev *model.URLRevision) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx,
+		`update urls
+         set original_url = $2, expires_at = $3, max_clicks = $4, redirect_code = $5, noindex = $6, updated_at = $7
+         where id = $1 and not is_deleted`,
+		m.ID, m.OriginalURL, m.ExpiresAt, m.MaxClicks, m.RedirectCode, m.NoIndex, m.UpdatedAt,
+	)
+	if dupErr := uniqueViolation(err); dupErr != nil {
+		return dupErr
+	}
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.NotFoundError("url not found")
+	}
+
+	_, err = tx.Exec(ctx,
+		`insert into url_revisions (id, url_id, original_url, expires_at, max_clicks, redirect_code, noindex, created_at)
+         values ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		rev.ID, rev.URLID, rev.OriginalURL, rev.ExpiresAt, rev.MaxClicks, rev.RedirectCode, rev.NoIndex, rev.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save revision: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresRepository) FindRevisions(ctx context.Context, urlID uuid.UUID) ([]*model.URLRevision, error) {
+	rows, err := r.pool.Query(ctx,
+		`select id, url_id, original_url, expires_at, max_clicks, redirect_code, noindex, created_at
+         from url_revisions
+         where url_id = $1
+         order by created_at desc, id desc`,
+		urlID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revs []*model.URLRevision
+	for rows.Next() {
+		var rev model.URLRevision
+		err := rows.Scan(&rev.ID, &rev.URLID, &rev.OriginalURL, &rev.ExpiresAt, &rev.MaxClicks,
+			&rev.RedirectCode, &rev.NoIndex, &rev.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		revs = append(revs, &rev)
+	}
+
+	return revs, rows.Err()
+}
+
+func (r *PostgresRepository) CountURLs(ctx context.Context) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, "select count(*) from urls where not is_deleted").Scan(&count)
+	return count, err
+}
+
+func (r *PostgresRepository) CountUsers(ctx context.Context) (int, error) {
+	var count int
+	err := r.pool.QueryRow(ctx, "select count(distinct user_id) from urls where user_id is not null and not is_deleted").Scan(&count)
+	return count, err
+}
+
+func (r *PostgresRepository) RegisterClick(ctx context.Context, hash string) (bool, error) {
+	tag, err := r.pool.Exec(ctx,
+		`update urls
+         set clicks = clicks + 1
+         where hash = $1 and (max_clicks is null or clicks < max_clicks)`,
+		hash,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// PurgeExpired переносит просроченные ссылки в urls_archive. Запись, уже
+// лежащая в архиве, перезаписывается, чтобы удаление из urls никогда не
+// теряло данные. История правок и переходов остаётся в url_revisions и
+// clicks. Условия отбора разнесены, чтобы каждое шло по своему индексу.
+func (r *PostgresRepository) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	tag, err := r.pool.Exec(ctx,
+		`with doomed as (
+             select id from urls where expires_at <= $1
+             union
+             select id from urls where max_clicks is not null and clicks >= max_clicks
+         ), expired as (
+             delete from urls
+             where id in (select id from doomed)
+             returning `+urlColumns+`
+         )
+         insert into urls_archive (`+urlColumns+`)
+         select `+urlColumns+` from expired
+         on conflict (id) do update set
+             hash = excluded.hash,
+             original_url = excluded.original_url,
+             created_at = excluded.created_at,
+             updated_at = excluded.updated_at,
+             correlation_id = excluded.correlation_id,
+             user_id = excluded.user_id,
+             is_deleted = excluded.is_deleted,
+             deleted_at = excluded.deleted_at,
+             expires_at = excluded.expires_at,
+             max_clicks = excluded.max_clicks,
+             clicks = excluded.clicks,
+             redirect_code = excluded.redirect_code,
+             noindex = excluded.noindex,
+             archived_at = now()`,
+		now,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+const urlColumns = "id, hash, original_url, created_at, updated_at, correlation_id, user_id, is_deleted, deleted_at, expires_at, max_clicks, clicks, redirect_code, noindex"
+
+func scanURL(row pgx.Row) (*model.URL, error) {
 	var m model.URL
 	err := row.Scan(
 		&m.ID,
@@ -117,14 +465,31 @@ func (r *PostgresRepository) FindByOriginalURL(ctx context.Context, original str
 		&m.CreatedAt,
 		&m.UpdatedAt,
 		&m.CorrelationID,
+		&m.UserID,
+		&m.IsDeleted,
+		&m.DeletedAt,
+		&m.ExpiresAt,
+		&m.MaxClicks,
+		&m.Clicks,
+		&m.RedirectCode,
+		&m.NoIndex,
 	)
-
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, nil
-		}
 		return nil, err
 	}
 
 	return &m, nil
 }
+
+// uniqueViolation переводит нарушение уникальности в доменную ошибку:
+// занятый хэш — HashConflictError, остальное — DuplicateEntryError.
+func uniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return nil
+	}
+	if pgErr.ConstraintName == "urls_hash_key" {
+		return errs.HashConflictError(pgErr.Message)
+	}
+	return errs.DuplicateEntryError(pgErr.Message)
+}
@@ -1,69 +1,189 @@
 package storage
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/amberdance/url-shortener/internal/domain/errs"
 	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/google/uuid"
 )
 
+// FileStorage держит ссылки в памяти и записывает каждое изменение в
+// журнал (JSON lines, см. journal.go). При старте журнал проигрывается,
+// а при разрастании сжимается до снимка текущего состояния.
 type FileStorage struct {
 	mu   sync.RWMutex
 	data map[string]*model.URL
-	path string
+	// clicks — переходы по ID ссылки.
+	clicks map[uuid.UUID][]*model.Click
+	// byID и byOriginal — хэш ссылки по ID и по исходному адресу.
+	byID       map[uuid.UUID]string
+	byOriginal map[string]string
+	// order — ID всех записей по возрастанию, для постраничной выборки.
+	order []uuid.UUID
+	// revisions — ревизии ссылок по ID ссылки, в порядке добавления.
+	revisions map[uuid.UUID][]*model.URLRevision
+	path      string
+	journal   *journal
+	opts      fileStorageOptions
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type fileStorageOptions struct {
+	sync             SyncMode
+	compactThreshold int
+	compactInterval  time.Duration
 }
 
-func NewFileStorage(path string) *FileStorage {
+type FileStorageOption func(*fileStorageOptions)
+
+// WithSyncMode задаёт, когда журнал сбрасывается на диск через fsync.
+func WithSyncMode(m SyncMode) FileStorageOption {
+	return func(o *fileStorageOptions) { o.sync = m }
+}
+
+// WithCompaction задаёт сжатие журнала: как только в нём накапливается
+// threshold устаревших записей, и дополнительно раз в interval. Нулевые
+// значения отключают соответствующий триггер.
+func WithCompaction(threshold int, interval time.Duration) FileStorageOption {
+	return func(o *fileStorageOptions) {
+		o.compactThreshold = threshold
+		o.compactInterval = interval
+	}
+}
+
+func NewFileStorage(path string, opts ...FileStorageOption) (*FileStorage, error) {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	s := &FileStorage{
-		data: make(map[string]*model.URL),
-		path: path,
+		data:       make(map[string]*model.URL),
+		clicks:     make(map[uuid.UUID][]*model.Click),
+		byID:       make(map[uuid.UUID]string),
+		byOriginal: make(map[string]string),
+		revisions:  make(map[uuid.UUID][]*model.URLRevision),
+		path:       path,
+		opts: fileStorageOptions{
+			sync:             SyncInterval,
+			compactThreshold: 10_000,
+			compactInterval:  10 * time.Minute,
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&s.opts)
 	}
 
-	if _, err := os.Stat(path); err == nil {
-		if err := s.loadFromDisk(); err != nil {
-			panic(err)
-		}
+	if err := s.loadFromDisk(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadClicks(); err != nil {
+		_ = s.journal.close()
+		return nil, err
 	}
 
-	return s
+	go s.run()
+
+	return s, nil
 }
 
 func (s *FileStorage) Ping(_ context.Context) error {
 	return nil
 }
 
+// Close останавливает фоновые задачи, сбрасывает журнал на диск и
+// закрывает его.
+func (s *FileStorage) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		err = s.journal.close()
+	})
+	return err
+}
+
 func (s *FileStorage) Put(u *model.URL) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.data[u.Hash] = u
-	return s.save()
+	if _, exists := s.byOriginal[u.OriginalURL]; exists {
+		return errs.DuplicateEntryError(fmt.Sprintf("duplicate url: %s", u.OriginalURL))
+	}
+	if _, exists := s.data[u.Hash]; exists {
+		return errs.HashConflictError(fmt.Sprintf("duplicate hash: %s", u.Hash))
+	}
+
+	if err := s.write(putRecord(u)); err != nil {
+		return err
+	}
+
+	s.set(u)
+	return nil
 }
 
-func (s *FileStorage) PutBatch(urls []*model.URL) error {
+// PutBatch сохраняет записи, не конфликтующие ни с хранилищем, ни с
+// предыдущими записями пачки, одной записью в журнал и возвращает ошибку
+// для каждой записи.
+func (s *FileStorage) PutBatch(urls []*model.URL) ([]error, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for _, u := range urls {
-		if _, exists := s.data[u.Hash]; exists {
-			return fmt.Errorf("duplicate hash: %s", u.Hash)
+	originals := make(map[string]struct{}, len(urls))
+	results := make([]error, len(urls))
+	hashes := make(map[string]struct{}, len(urls))
+	var accepted []*model.URL
+	for i, u := range urls {
+		_, inStorage := s.byOriginal[u.OriginalURL]
+		if _, exists := originals[u.OriginalURL]; exists || inStorage {
+			results[i] = errs.DuplicateEntryError(fmt.Sprintf("duplicate url: %s", u.OriginalURL))
+			continue
 		}
+		_, inBatch := hashes[u.Hash]
+		if _, exists := s.data[u.Hash]; exists || inBatch {
+			results[i] = errs.HashConflictError(fmt.Sprintf("duplicate hash: %s", u.Hash))
+			continue
+		}
+		originals[u.OriginalURL] = struct{}{}
+		hashes[u.Hash] = struct{}{}
+		accepted = append(accepted, u)
+	}
+
+	if len(accepted) == 0 {
+		return results, nil
 	}
 
-	for _, u := range urls {
-		s.data[u.Hash] = u
+	records := make([]journalRecord, 0, len(accepted))
+	for _, u := range accepted {
+		records = append(records, putRecord(u))
+	}
+	if err := s.write(records...); err != nil {
+		return nil, err
 	}
 
-	return s.save()
+	for _, u := range accepted {
+		s.set(u)
+	}
+	return results, nil
 }
 
 func (s *FileStorage) GetByHash(hash string) (*model.URL, bool) {
@@ -78,57 +198,424 @@ func (s *FileStorage) GetByOriginalURL(original string) (*model.URL, bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	hash, ok := s.byOriginal[original]
+	if !ok {
+		return nil, false
+	}
+	return s.data[hash], true
+}
+
+func (s *FileStorage) GetByUserID(userID uuid.UUID) []*model.URL {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var urls []*model.URL
 	for _, u := range s.data {
-		if u.OriginalURL == original {
-			return u, true
+		if u.UserID != nil && *u.UserID == userID && !u.IsDeleted {
+			urls = append(urls, u)
 		}
 	}
-	return nil, false
+	return urls
 }
 
-func (s *FileStorage) loadFromDisk() error {
-	file, err := os.Open(s.path)
-	if err != nil {
+func (s *FileStorage) MarkDeleted(byUser map[uuid.UUID][]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var changed []*model.URL
+	for userID, hashes := range byUser {
+		for _, hash := range hashes {
+			u, ok := s.data[hash]
+			if !ok || u.IsDeleted || u.UserID == nil || *u.UserID != userID {
+				continue
+			}
+			deleted := *u
+			deleted.IsDeleted = true
+			deleted.DeletedAt = &now
+			changed = append(changed, &deleted)
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	records := make([]journalRecord, 0, len(changed))
+	for _, u := range changed {
+		records = append(records, putRecord(u))
+	}
+	if err := s.write(records...); err != nil {
 		return err
 	}
-	defer file.Close()
 
-	var records []model.URL
-	if err := json.NewDecoder(file).Decode(&records); err != nil {
+	for _, u := range changed {
+		s.set(u)
+	}
+	return nil
+}
+
+// Revise переносит изменяемые поля u на хранимую ссылку с тем же хэшем и ID
+// и добавляет ревизию rev одной записью в журнал. Удалённые ссылки не
+// меняются.
+func (s *FileStorage) Revise(u *model.URL, rev *model.URLRevision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.data[u.Hash]
+	if !ok || old.ID != u.ID || old.IsDeleted {
+		return errs.NotFoundError("url not found")
+	}
+	if hash, exists := s.byOriginal[u.OriginalURL]; exists && hash != u.Hash {
+		return errs.DuplicateEntryError("url already exists")
+	}
+
+	revised := *old
+	revised.ApplyEdits(u)
+	if err := s.write(putRecord(&revised), revisionRecord(rev)); err != nil {
 		return err
 	}
 
+	s.set(&revised)
+	s.revisions[u.ID] = append(s.revisions[u.ID], rev)
+	return nil
+}
+
+// Revisions возвращает ревизии ссылки urlID в порядке добавления.
+func (s *FileStorage) Revisions(urlID uuid.UUID) []*model.URLRevision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.revisions[urlID])
+}
+
+// RegisterClick увеличивает счётчик переходов, если лимит ещё не исчерпан.
+func (s *FileStorage) RegisterClick(hash string) (bool, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for _, m := range records {
-		mCopy := m
-		s.data[m.Hash] = &mCopy
+	u, ok := s.data[hash]
+	if !ok {
+		return false, errs.NotFoundError("url not found")
+	}
+	if u.MaxClicks != nil && u.Clicks >= *u.MaxClicks {
+		return false, nil
+	}
+
+	clicked := *u
+	clicked.Clicks++
+	if err := s.write(putRecord(&clicked)); err != nil {
+		return false, err
 	}
 
+	s.set(&clicked)
+	return true, nil
+}
+
+// Remove удаляет записи, для которых drop возвращает true.
+func (s *FileStorage) Remove(drop func(u *model.URL) bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []journalRecord
+	for hash, u := range s.data {
+		if drop(u) {
+			records = append(records, deleteRecord(hash))
+		}
+	}
+
+	if len(records) == 0 {
+		return 0, nil
+	}
+	if err := s.write(records...); err != nil {
+		return 0, err
+	}
+
+	for _, r := range records {
+		s.drop(r.Hash)
+	}
+	return len(records), nil
+}
+
+// Page возвращает страницу ссылок по курсору q.After.
+func (s *FileStorage) Page(q model.URLPageQuery) []*model.URL {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	start, _ := slices.BinarySearchFunc(s.order, q.After, compareIDs)
+	var page []*model.URL
+	for _, id := range s.order[start:] {
+		if len(page) == q.Limit {
+			break
+		}
+		if u := s.data[s.byID[id]]; inPage(u, q) {
+			page = append(page, u)
+		}
+	}
+	return page
+}
+
+// Compact перезаписывает журнал снимком текущего состояния.
+func (s *FileStorage) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.compact()
+}
+
+// Stats возвращает количество неудалённых ссылок и их уникальных владельцев.
+func (s *FileStorage) Stats() (urls int, users int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	owners := make(map[uuid.UUID]struct{})
+	for _, u := range s.data {
+		if u.IsDeleted {
+			continue
+		}
+		urls++
+		if u.UserID != nil {
+			owners[*u.UserID] = struct{}{}
+		}
+	}
+	return urls, len(owners)
+}
+
+// AppendClicks дописывает переходы в отдельный файл построчно в JSON.
+func (s *FileStorage) AppendClicks(clicks []*model.Click) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.clicksPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	for _, c := range clicks {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, c := range clicks {
+		s.clicks[c.URLID] = append(s.clicks[c.URLID], c)
+	}
 	return nil
 }
 
-func (s *FileStorage) save() error {
-	tmp := s.path + ".tmp"
+// ClickCounts возвращает число переходов по каждой из ссылок urlIDs.
+func (s *FileStorage) ClickCounts(urlIDs []uuid.UUID) map[uuid.UUID]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return countClicks(s.clicks, urlIDs)
+}
 
-	file, err := os.Create(tmp)
+// Clicks возвращает переходы по ссылке с идентификатором urlID.
+func (s *FileStorage) Clicks(urlID uuid.UUID) []*model.Click {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.clicks[urlID])
+}
+
+func (s *FileStorage) clicksPath() string {
+	return s.path + ".clicks.jsonl"
+}
+
+func (s *FileStorage) loadClicks() error {
+	file, err := os.Open(s.clicksPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	records := make([]model.URL, 0, len(s.data))
+	dec := json.NewDecoder(file)
+	for dec.More() {
+		var c model.Click
+		if err := dec.Decode(&c); err != nil {
+			return err
+		}
+		s.clicks[c.URLID] = append(s.clicks[c.URLID], &c)
+	}
+
+	return nil
+}
+
+// loadFromDisk проигрывает журнал. Файл в старом формате (JSON-массив) и
+// журнал с оборванной последней записью сразу перезаписываются снимком.
+func (s *FileStorage) loadFromDisk() error {
+	records, rewrite, err := readJournal(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", s.path, err)
+	}
+
+	for _, r := range records {
+		switch r.Op {
+		case opPut:
+			s.set(r.URL)
+		case opDelete:
+			s.drop(r.Hash)
+		case opRevision:
+			s.revisions[r.Revision.URLID] = append(s.revisions[r.Revision.URLID], r.Revision)
+		}
+	}
+
+	if rewrite {
+		return s.compact()
+	}
+
+	s.journal, err = openJournal(s.path, s.opts.sync)
+	if err != nil {
+		return err
+	}
+	s.journal.records = len(records)
+	return nil
+}
+
+// Size возвращает текущий размер журнала в байтах.
+func (s *FileStorage) Size() (int64, error) {
+	fi, err := os.Stat(s.path)
+	if err != nil {
+		return 0, err
+	}
+	return fi.Size(), nil
+}
+
+// write дописывает записи в журнал и при необходимости сжимает его.
+func (s *FileStorage) write(records ...journalRecord) error {
+	if err := s.journal.append(records...); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	if s.opts.compactThreshold > 0 && s.journal.records-s.live() >= s.opts.compactThreshold {
+		// Записи уже в журнале, поэтому ошибка сжатия не отменяет операцию.
+		_ = s.compact()
+	}
+	return nil
+}
+
+// set сохраняет ссылку u в памяти, поддерживая индексы.
+func (s *FileStorage) set(u *model.URL) {
+	old, ok := s.data[u.Hash]
+	if ok {
+		s.unlinkOriginal(old)
+	}
+	if ok && old.ID != u.ID {
+		s.unorder(old)
+	}
+	if !ok || old.ID != u.ID {
+		s.order = insertID(s.order, u.ID)
+	}
+	s.data[u.Hash] = u
+	s.byID[u.ID] = u.Hash
+	if !u.IsDeleted {
+		s.byOriginal[u.OriginalURL] = u.Hash
+	}
+}
+
+// drop удаляет ссылку с хэшем hash вместе с её ревизиями.
+func (s *FileStorage) drop(hash string) {
+	if u, ok := s.data[hash]; ok {
+		delete(s.revisions, u.ID)
+		s.unlinkOriginal(u)
+		s.unorder(u)
+	}
+	delete(s.data, hash)
+}
+
+// unlinkOriginal убирает u из индекса по адресу. Удалённые ссылки в нём не
+// держатся, поэтому адрес может уже принадлежать другой записи.
+func (s *FileStorage) unlinkOriginal(u *model.URL) {
+	if s.byOriginal[u.OriginalURL] == u.Hash {
+		delete(s.byOriginal, u.OriginalURL)
+	}
+}
+
+// unorder убирает u из индекса по ID, если он ещё указывает на эту запись.
+func (s *FileStorage) unorder(u *model.URL) {
+	if s.byID[u.ID] == u.Hash {
+		delete(s.byID, u.ID)
+		s.order = removeID(s.order, u.ID)
+	}
+}
+
+// live возвращает число записей, которые попадут в снимок журнала.
+func (s *FileStorage) live() int {
+	n := len(s.data)
+	for _, revs := range s.revisions {
+		n += len(revs)
+	}
+	return n
+}
+
+func (s *FileStorage) compact() error {
+	records := make([]journalRecord, 0, s.live())
 	for _, u := range s.data {
-		records = append(records, *u)
+		records = append(records, putRecord(u))
+	}
+	for _, revs := range s.revisions {
+		for _, rev := range revs {
+			records = append(records, revisionRecord(rev))
+		}
 	}
 
-	enc := json.NewEncoder(file)
-	enc.SetIndent("", "  ")
+	if err := writeSnapshot(s.path, records); err != nil {
+		return err
+	}
 
-	if err := enc.Encode(records); err != nil {
+	j, err := openJournal(s.path, s.opts.sync)
+	if err != nil {
 		return err
 	}
+	j.records = len(records)
+
+	// Старый дескриптор указывает на заменённый файл, его данные уже в снимке.
+	if s.journal != nil {
+		_ = s.journal.file.Close()
+	}
+	s.journal = j
+	return nil
+}
+
+func (s *FileStorage) run() {
+	defer close(s.done)
+
+	syncTicker := time.NewTicker(time.Second)
+	defer syncTicker.Stop()
+
+	var compactC <-chan time.Time
+	if s.opts.compactInterval > 0 {
+		compactTicker := time.NewTicker(s.opts.compactInterval)
+		defer compactTicker.Stop()
+		compactC = compactTicker.C
+	}
 
-	return os.Rename(tmp, s.path)
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-syncTicker.C:
+			if s.opts.sync == SyncInterval {
+				s.mu.Lock()
+				_ = s.journal.sync()
+				s.mu.Unlock()
+			}
+		case <-compactC:
+			s.mu.Lock()
+			if s.journal.records > s.live() {
+				_ = s.compact()
+			}
+			s.mu.Unlock()
+		}
+	}
 }
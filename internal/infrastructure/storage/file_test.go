@@ -0,0 +1,239 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestURL(t *testing.T, original, hash string, userID *uuid.UUID) *model.URL {
+	m, err := model.NewURL(original, hash, nil, userID, time.Now())
+	require.NoError(t, err)
+	return m
+}
+
+func openFileStorage(t *testing.T, path string, opts ...FileStorageOption) *FileStorage {
+	s, err := NewFileStorage(path, opts...)
+	require.NoError(t, err)
+	return s
+}
+
+func countLines(t *testing.T, path string) int {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	n := 0
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		n++
+	}
+	return n
+}
+
+func TestFileStorage_ReplaysJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+	userID := uuid.New()
+
+	s := openFileStorage(t, path, WithSyncMode(SyncAlways), WithCompaction(0, 0))
+	require.NoError(t, s.Put(newTestURL(t, "https://a.example", "a", &userID)))
+	itemErrs, err := s.PutBatch([]*model.URL{
+		newTestURL(t, "https://b.example", "b", &userID),
+		newTestURL(t, "https://c.example", "c", nil),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []error{nil, nil}, itemErrs)
+	require.NoError(t, s.MarkDeleted(map[uuid.UUID][]string{userID: []string{"b"}}))
+	removed, err := s.Remove(func(u *model.URL) bool { return u.Hash == "c" })
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	require.NoError(t, s.Close())
+
+	assert.Equal(t, 5, countLines(t, path), "каждое изменение — одна строка журнала")
+
+	reopened := openFileStorage(t, path)
+	defer reopened.Close()
+
+	_, ok := reopened.GetByHash("a")
+	assert.True(t, ok)
+	b, ok := reopened.GetByHash("b")
+	require.True(t, ok)
+	assert.True(t, b.IsDeleted)
+	_, ok = reopened.GetByHash("c")
+	assert.False(t, ok)
+}
+
+func TestFileStorage_ConvertsLegacyArray(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+	legacy := []model.URL{*newTestURL(t, "https://a.example", "a", nil), *newTestURL(t, "https://b.example", "b", nil)}
+	data, err := json.MarshalIndent(legacy, "", "  ")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	s := openFileStorage(t, path)
+	_, ok := s.GetByHash("b")
+	assert.True(t, ok)
+	require.NoError(t, s.Close())
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(raw), `{"op":"put"`))
+	assert.Equal(t, 2, countLines(t, path))
+}
+
+func TestFileStorage_IgnoresTornTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+
+	s := openFileStorage(t, path, WithSyncMode(SyncAlways))
+	require.NoError(t, s.Put(newTestURL(t, "https://a.example", "a", nil)))
+	require.NoError(t, s.Close())
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"op":"put","url":{"Hash":"b"`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened := openFileStorage(t, path)
+	_, ok := reopened.GetByHash("a")
+	assert.True(t, ok)
+	require.NoError(t, reopened.Put(newTestURL(t, "https://c.example", "c", nil)))
+	require.NoError(t, reopened.Close())
+
+	again := openFileStorage(t, path)
+	defer again.Close()
+	_, ok = again.GetByHash("c")
+	assert.True(t, ok)
+}
+
+func TestJournal_RollbackCutsPartialWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+
+	j, err := openJournal(path, SyncNever)
+	require.NoError(t, err)
+	require.NoError(t, j.append(putRecord(newTestURL(t, "https://a.example", "a", nil))))
+	size := j.size
+
+	// Имитируем запись, оборвавшуюся на середине строки.
+	_, err = j.file.WriteString(`{"op":"put","url":{"Hash":"b"`)
+	require.NoError(t, err)
+	assert.ErrorIs(t, j.rollback(os.ErrClosed), os.ErrClosed)
+	require.NoError(t, j.close())
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, size, fi.Size())
+	assert.Equal(t, 1, countLines(t, path))
+}
+
+func TestNewFileStorage_ReturnsLoadError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+	require.NoError(t, os.WriteFile(path, []byte("garbage\n{\"op\":\"delete\",\"hash\":\"a\"}\n"), 0644))
+
+	_, err := NewFileStorage(path)
+	assert.Error(t, err)
+}
+
+func TestFileStorage_CompactsAfterThreshold(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+	maxClicks := 100
+
+	s := openFileStorage(t, path, WithCompaction(10, 0))
+	u := newTestURL(t, "https://a.example", "a", nil)
+	u.MaxClicks = &maxClicks
+	require.NoError(t, s.Put(u))
+	for range 25 {
+		ok, err := s.RegisterClick("a")
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	require.NoError(t, s.Close())
+
+	assert.Less(t, countLines(t, path), 11)
+
+	reopened := openFileStorage(t, path)
+	defer reopened.Close()
+	got, ok := reopened.GetByHash("a")
+	require.True(t, ok)
+	assert.Equal(t, 25, got.Clicks)
+}
+
+func TestFileStorage_RevisionsSurviveRestartAndCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+
+	s := openFileStorage(t, path, WithSyncMode(SyncNever), WithCompaction(0, 0))
+	u := newTestURL(t, "https://old.example", "h1", nil)
+	require.NoError(t, s.Put(u))
+	require.NoError(t, s.Put(newTestURL(t, "https://taken.example", "h2", nil)))
+
+	revised, rev, err := u.Revise(model.URLChange{OriginalURL: ptr("https://taken.example")}, time.Now())
+	require.NoError(t, err)
+	assert.ErrorContains(t, s.Revise(revised, rev), "already exists")
+
+	revised, rev, err = u.Revise(model.URLChange{OriginalURL: ptr("https://new.example")}, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, s.Revise(revised, rev))
+	require.NoError(t, s.Close())
+
+	s = openFileStorage(t, path, WithSyncMode(SyncNever), WithCompaction(0, 0))
+	got, ok := s.GetByHash("h1")
+	require.True(t, ok)
+	assert.Equal(t, "https://new.example", got.OriginalURL)
+	require.Len(t, s.Revisions(u.ID), 1)
+	assert.Equal(t, "https://old.example", s.Revisions(u.ID)[0].OriginalURL)
+
+	require.NoError(t, s.Compact())
+	require.NoError(t, s.Close())
+
+	s = openFileStorage(t, path, WithSyncMode(SyncNever), WithCompaction(0, 0))
+	defer s.Close()
+	assert.Len(t, s.Revisions(u.ID), 1)
+
+	removed, err := s.Remove(func(u *model.URL) bool { return u.Hash == "h1" })
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Empty(t, s.Revisions(u.ID))
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestFileStorage_ReviseKeepsConcurrentChanges(t *testing.T) {
+	s := openFileStorage(t, filepath.Join(t.TempDir(), "db.json"), WithCompaction(0, 0))
+	defer s.Close()
+
+	userID := uuid.New()
+	u := newTestURL(t, "https://old.example", "h1", &userID)
+	require.NoError(t, s.Put(u))
+
+	// Ссылка прочитана до перехода, а правка сохраняется после него.
+	revised, rev, err := u.Revise(model.URLChange{OriginalURL: ptr("https://new.example")}, time.Now())
+	require.NoError(t, err)
+	ok, err := s.RegisterClick("h1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, s.Revise(revised, rev))
+
+	got, found := s.GetByHash("h1")
+	require.True(t, found)
+	assert.Equal(t, "https://new.example", got.OriginalURL)
+	assert.Equal(t, 1, got.Clicks)
+	_, found = s.GetByOriginalURL("https://old.example")
+	assert.False(t, found)
+
+	require.NoError(t, s.MarkDeleted(map[uuid.UUID][]string{userID: []string{"h1"}}))
+	revised, rev, err = got.Revise(model.URLChange{OriginalURL: ptr("https://newer.example")}, time.Now())
+	require.NoError(t, err)
+	assert.ErrorContains(t, s.Revise(revised, rev), "not found")
+
+	got, _ = s.GetByHash("h1")
+	assert.True(t, got.IsDeleted)
+}
@@ -0,0 +1,266 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+)
+
+// SyncMode определяет, когда журнал FileStorage сбрасывается на диск.
+type SyncMode string
+
+const (
+	// SyncAlways вызывает fsync после каждой записи.
+	SyncAlways SyncMode = "always"
+	// SyncInterval вызывает fsync раз в секунду: при сбое теряется не больше
+	// секунды изменений.
+	SyncInterval SyncMode = "interval"
+	// SyncNever оставляет сброс на усмотрение ОС.
+	SyncNever SyncMode = "never"
+)
+
+const (
+	opPut      = "put"
+	opDelete   = "delete"
+	opRevision = "revision"
+)
+
+// journalRecord — одна строка журнала. put создаёт или заменяет ссылку
+// целиком, delete удаляет её по хэшу вместе с ревизиями, revision
+// добавляет ревизию ссылки.
+type journalRecord struct {
+	Op       string             `json:"op"`
+	URL      *model.URL         `json:"url,omitempty"`
+	Hash     string             `json:"hash,omitempty"`
+	Revision *model.URLRevision `json:"revision,omitempty"`
+}
+
+func putRecord(u *model.URL) journalRecord {
+	return journalRecord{Op: opPut, URL: u}
+}
+
+func deleteRecord(hash string) journalRecord {
+	return journalRecord{Op: opDelete, Hash: hash}
+}
+
+func revisionRecord(rev *model.URLRevision) journalRecord {
+	return journalRecord{Op: opRevision, Revision: rev}
+}
+
+type journal struct {
+	file    *os.File
+	mode    SyncMode
+	dirty   bool
+	records int
+	// size — длина файла после последней успешной записи.
+	size int64
+}
+
+func openJournal(path string, mode SyncMode) (*journal, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &journal{file: f, mode: mode, size: fi.Size()}, nil
+}
+
+// append пишет все записи одним вызовом write, чтобы пачка не перемешалась
+// с другими записями. Если запись не удалась, файл обрезается до прежней
+// длины, чтобы в журнале не осталось оборванной строки.
+func (j *journal) append(records ...journalRecord) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	if _, err := j.file.Write(buf.Bytes()); err != nil {
+		return j.rollback(err)
+	}
+
+	if j.mode == SyncAlways {
+		if err := j.file.Sync(); err != nil {
+			return j.rollback(err)
+		}
+	} else {
+		j.dirty = true
+	}
+
+	j.size += int64(buf.Len())
+	j.records += len(records)
+	return nil
+}
+
+// rollback отрезает от файла всё, что было дописано после последней
+// успешной записи.
+func (j *journal) rollback(err error) error {
+	if truncErr := j.file.Truncate(j.size); truncErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to truncate journal: %w", truncErr))
+	}
+	return err
+}
+
+func (j *journal) sync() error {
+	if !j.dirty {
+		return nil
+	}
+	j.dirty = false
+	return j.file.Sync()
+}
+
+func (j *journal) close() error {
+	return errors.Join(j.sync(), j.file.Close())
+}
+
+// readJournal читает журнал целиком. rewrite сообщает, что файл нужно
+// перезаписать снимком: он в старом формате или последняя запись оборвана.
+func readJournal(path string) (records []journalRecord, rewrite bool, err error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	if isLegacyArray(r) {
+		records, err = readLegacyArray(r)
+		return records, true, err
+	}
+
+	for line := 1; ; line++ {
+		b, readErr := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(b)) > 0 {
+			var rec journalRecord
+			if err := json.Unmarshal(b, &rec); err != nil {
+				if errors.Is(readErr, io.EOF) {
+					// Запись оборвалась при сбое во время дописывания.
+					return records, true, nil
+				}
+				return nil, false, fmt.Errorf("line %d: %w", line, err)
+			}
+			if !rec.valid() {
+				return nil, false, fmt.Errorf("line %d: invalid record", line)
+			}
+			records = append(records, rec)
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			return records, false, nil
+		}
+		if readErr != nil {
+			return nil, false, readErr
+		}
+	}
+}
+
+func (r journalRecord) valid() bool {
+	switch r.Op {
+	case opPut:
+		return r.URL != nil && r.URL.Hash != ""
+	case opDelete:
+		return r.Hash != ""
+	case opRevision:
+		return r.Revision != nil
+	}
+	return false
+}
+
+// isLegacyArray проверяет, что файл записан в прежнем формате — одним
+// JSON-массивом.
+func isLegacyArray(r *bufio.Reader) bool {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return false
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = r.ReadByte()
+		default:
+			return b[0] == '['
+		}
+	}
+}
+
+func readLegacyArray(r io.Reader) ([]journalRecord, error) {
+	var urls []*model.URL
+	if err := json.NewDecoder(r).Decode(&urls); err != nil {
+		return nil, err
+	}
+
+	records := make([]journalRecord, 0, len(urls))
+	for _, u := range urls {
+		records = append(records, putRecord(u))
+	}
+	return records, nil
+}
+
+// writeSnapshot атомарно заменяет файл path журналом из records: пишет во
+// временный файл, сбрасывает его на диск и переименовывает.
+func writeSnapshot(path string, records []journalRecord) error {
+	tmp := path + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, r := range records {
+		if err = enc.Encode(r); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir сбрасывает на диск запись о переименовании файла в каталоге.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil && !errors.Is(err, os.ErrInvalid) {
+		return err
+	}
+	return nil
+}
@@ -2,23 +2,306 @@ package storage
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"sync"
 
+	"github.com/amberdance/url-shortener/internal/domain/errs"
 	"github.com/amberdance/url-shortener/internal/domain/model"
 	"github.com/google/uuid"
 )
 
+// InMemoryStorage хранит ссылки в памяти. Помимо основной таблицы по ID
+// ведутся индексы по хэшу, исходному адресу и владельцу; все они меняются
+// только под одной блокировкой, поэтому проверка уникальности и вставка
+// атомарны. Удалённые ссылки в индекс по адресу не попадают, и адрес можно
+// сократить заново.
 type InMemoryStorage struct {
-	Data map[uuid.UUID]*model.URL
-	Mu   sync.RWMutex
+	mu         sync.RWMutex
+	byID       map[uuid.UUID]*model.URL
+	byHash     map[string]uuid.UUID
+	byOriginal map[string]uuid.UUID
+	byUser     map[uuid.UUID]map[uuid.UUID]struct{}
+	// activeByUser — число неудалённых ссылок владельца, для Stats.
+	activeByUser map[uuid.UUID]int
+	// order — ID всех записей по возрастанию, для постраничной выборки.
+	order  []uuid.UUID
+	active int
+	// clicks — переходы по ID ссылки.
+	clicks    map[uuid.UUID][]*model.Click
+	revisions map[uuid.UUID][]*model.URLRevision
+}
+
+func NewInMemoryStorage() *InMemoryStorage {
+	return &InMemoryStorage{
+		byID:         make(map[uuid.UUID]*model.URL),
+		byHash:       make(map[string]uuid.UUID),
+		byOriginal:   make(map[string]uuid.UUID),
+		byUser:       make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		activeByUser: make(map[uuid.UUID]int),
+		clicks:       make(map[uuid.UUID][]*model.Click),
+		revisions:    make(map[uuid.UUID][]*model.URLRevision),
+	}
 }
 
 func (s *InMemoryStorage) Ping(_ context.Context) error {
 	return nil
 }
 
-func NewInMemoryStorage() *InMemoryStorage {
-	return &InMemoryStorage{
-		Data: make(map[uuid.UUID]*model.URL),
+func (s *InMemoryStorage) Put(u *model.URL) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.byOriginal[u.OriginalURL]; exists {
+		return errs.DuplicateEntryError("url already exists")
+	}
+	if _, exists := s.byHash[u.Hash]; exists {
+		return errs.HashConflictError("hash already exists")
+	}
+
+	s.insert(u)
+	return nil
+}
+
+// PutBatch сохраняет записи, не конфликтующие ни с хранилищем, ни с
+// предыдущими записями пачки, и возвращает ошибку для каждой записи.
+func (s *InMemoryStorage) PutBatch(urls []*model.URL) []error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := make([]error, len(urls))
+	for i, u := range urls {
+		if _, exists := s.byOriginal[u.OriginalURL]; exists {
+			results[i] = errs.DuplicateEntryError(fmt.Sprintf("duplicate url: %s", u.OriginalURL))
+			continue
+		}
+		if _, exists := s.byHash[u.Hash]; exists {
+			results[i] = errs.HashConflictError(fmt.Sprintf("duplicate hash: %s", u.Hash))
+			continue
+		}
+		s.insert(u)
+	}
+	return results
+}
+
+func (s *InMemoryStorage) GetByHash(hash string) (*model.URL, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.byHash[hash]
+	if !ok {
+		return nil, false
+	}
+	return s.byID[id], true
+}
+
+func (s *InMemoryStorage) GetByOriginalURL(original string) (*model.URL, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id, ok := s.byOriginal[original]
+	if !ok {
+		return nil, false
+	}
+	return s.byID[id], true
+}
+
+func (s *InMemoryStorage) GetByUserID(userID uuid.UUID) []*model.URL {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var urls []*model.URL
+	for id := range s.byUser[userID] {
+		if u := s.byID[id]; !u.IsDeleted {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// Update применяет fn к копии записи с хэшем hash и сохраняет копию, если
+// fn вернула true. Возвращает false, если записи нет. Уникальность Hash и
+// OriginalURL после fn не проверяется.
+func (s *InMemoryStorage) Update(hash string, fn func(u *model.URL) bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.byHash[hash]
+	if !ok {
+		return false
+	}
+
+	old := s.byID[id]
+	updated := *old
+	if fn(&updated) {
+		s.replace(old, &updated)
+	}
+	return true
+}
+
+// Revise переносит изменяемые поля u на хранимую ссылку с тем же ID и
+// добавляет ревизию rev. Удалённые ссылки не меняются.
+func (s *InMemoryStorage) Revise(u *model.URL, rev *model.URLRevision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.byID[u.ID]
+	if !ok || old.Hash != u.Hash || old.IsDeleted {
+		return errs.NotFoundError("url not found")
+	}
+	if id, exists := s.byOriginal[u.OriginalURL]; exists && id != u.ID {
+		return errs.DuplicateEntryError("url already exists")
+	}
+
+	revised := *old
+	revised.ApplyEdits(u)
+	s.replace(old, &revised)
+	s.revisions[u.ID] = append(s.revisions[u.ID], rev)
+	return nil
+}
+
+// Revisions возвращает ревизии ссылки urlID в порядке добавления.
+func (s *InMemoryStorage) Revisions(urlID uuid.UUID) []*model.URLRevision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.revisions[urlID])
+}
+
+// Remove удаляет записи, для которых drop возвращает true.
+func (s *InMemoryStorage) Remove(drop func(u *model.URL) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := 0
+	for _, u := range s.byID {
+		if drop(u) {
+			s.delete(u)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Page возвращает страницу ссылок по курсору q.After.
+func (s *InMemoryStorage) Page(q model.URLPageQuery) []*model.URL {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if q.UserID != nil {
+		return selectPage(func(yield func(*model.URL) bool) {
+			for id := range s.byUser[*q.UserID] {
+				if !yield(s.byID[id]) {
+					return
+				}
+			}
+		}, q)
+	}
+
+	start, _ := slices.BinarySearchFunc(s.order, q.After, compareIDs)
+	var page []*model.URL
+	for _, id := range s.order[start:] {
+		if len(page) == q.Limit {
+			break
+		}
+		if u := s.byID[id]; inPage(u, q) {
+			page = append(page, u)
+		}
+	}
+	return page
+}
+
+// Stats возвращает количество неудалённых ссылок и их уникальных владельцев.
+func (s *InMemoryStorage) Stats() (urls int, users int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.active, len(s.activeByUser)
+}
+
+func (s *InMemoryStorage) AppendClicks(clicks []*model.Click) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range clicks {
+		s.clicks[c.URLID] = append(s.clicks[c.URLID], c)
+	}
+}
+
+// ClickCounts возвращает число переходов по каждой из ссылок urlIDs.
+func (s *InMemoryStorage) ClickCounts(urlIDs []uuid.UUID) map[uuid.UUID]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return countClicks(s.clicks, urlIDs)
+}
+
+// Clicks возвращает переходы по ссылке с идентификатором urlID.
+func (s *InMemoryStorage) Clicks(urlID uuid.UUID) []*model.Click {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.clicks[urlID])
+}
+
+func (s *InMemoryStorage) insert(u *model.URL) {
+	s.index(u)
+	s.order = insertID(s.order, u.ID)
+}
+
+func (s *InMemoryStorage) delete(u *model.URL) {
+	s.unindex(u)
+	delete(s.revisions, u.ID)
+
+	s.order = removeID(s.order, u.ID)
+}
+
+// replace меняет запись, сохраняя её ID, поэтому order не трогает.
+func (s *InMemoryStorage) replace(old, updated *model.URL) {
+	s.unindex(old)
+	s.index(updated)
+}
+
+func (s *InMemoryStorage) index(u *model.URL) {
+	s.byID[u.ID] = u
+	s.byHash[u.Hash] = u.ID
+	if u.UserID != nil {
+		owned, ok := s.byUser[*u.UserID]
+		if !ok {
+			owned = make(map[uuid.UUID]struct{})
+			s.byUser[*u.UserID] = owned
+		}
+		owned[u.ID] = struct{}{}
+	}
+	if !u.IsDeleted {
+		s.byOriginal[u.OriginalURL] = u.ID
+		s.active++
+		if u.UserID != nil {
+			s.activeByUser[*u.UserID]++
+		}
+	}
+}
+
+func (s *InMemoryStorage) unindex(u *model.URL) {
+	delete(s.byID, u.ID)
+	delete(s.byHash, u.Hash)
+	if s.byOriginal[u.OriginalURL] == u.ID {
+		delete(s.byOriginal, u.OriginalURL)
+	}
+	if u.UserID != nil {
+		owned := s.byUser[*u.UserID]
+		delete(owned, u.ID)
+		if len(owned) == 0 {
+			delete(s.byUser, *u.UserID)
+		}
+	}
+	if !u.IsDeleted {
+		s.active--
+		if u.UserID != nil {
+			s.activeByUser[*u.UserID]--
+			if s.activeByUser[*u.UserID] == 0 {
+				delete(s.activeByUser, *u.UserID)
+			}
+		}
 	}
 }
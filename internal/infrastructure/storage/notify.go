@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// linkChannel — канал LISTEN/NOTIFY, по которому экземпляры сервиса
+// сообщают друг другу об изменении ссылок.
+const linkChannel = "url_changes"
+
+// maxNotifyPayload — запас до предела PostgreSQL в 8000 байт на сообщение.
+const maxNotifyPayload = 7000
+
+const (
+	LinkUpserted = "upsert"
+	LinkDeleted  = "delete"
+	// LinksPurged — удалён заранее неизвестный набор ссылок.
+	LinksPurged = "purge"
+	// LinksResync приходит от самой подписки после переподключения: пока
+	// соединения не было, события могли потеряться.
+	LinksResync = "resync"
+)
+
+// LinkEvent описывает изменение ссылок с кодами Hashes. Origin —
+// экземпляр-источник; свои события подписка не доставляет.
+type LinkEvent struct {
+	Op     string   `json:"op"`
+	Hashes []string `json:"hashes,omitempty"`
+	Origin string   `json:"origin"`
+}
+
+// Publish рассылает событие всем подписчикам. Длинный список кодов
+// делится на несколько сообщений.
+func (s *PostgresStorage) Publish(ctx context.Context, ev LinkEvent) error {
+	ev.Origin = s.instance
+
+	for _, payload := range encodeLinkEvent(ev) {
+		if _, err := s.pool.Exec(ctx, "select pg_notify($1, $2)", linkChannel, payload); err != nil {
+			return fmt.Errorf("failed to publish link event: %w", err)
+		}
+	}
+	return nil
+}
+
+func encodeLinkEvent(ev LinkEvent) []string {
+	if len(ev.Hashes) == 0 {
+		b, _ := json.Marshal(ev)
+		return []string{string(b)}
+	}
+
+	var payloads []string
+	rest := ev.Hashes
+	for len(rest) > 0 {
+		n, size := 0, 0
+		for n < len(rest) && (n == 0 || size+len(rest[n])+3 <= maxNotifyPayload) {
+			size += len(rest[n]) + 3
+			n++
+		}
+
+		chunk := ev
+		chunk.Hashes = rest[:n]
+		b, _ := json.Marshal(chunk)
+		payloads = append(payloads, string(b))
+		rest = rest[n:]
+	}
+	return payloads
+}
+
+// LinkSubscription держит выделенное соединение с LISTEN и при обрыве
+// переподключается с растущей паузой.
+type LinkSubscription struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+const (
+	minReconnectDelay = 100 * time.Millisecond
+	maxReconnectDelay = 5 * time.Second
+)
+
+// Subscribe вызывает handle для каждого события других экземпляров.
+// Ошибки соединения передаются в onError, после чего подписка
+// переподключается и доставляет LinksResync.
+func (s *PostgresStorage) Subscribe(handle func(LinkEvent), onError func(error)) *LinkSubscription {
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := &LinkSubscription{cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(sub.done)
+
+		delay := minReconnectDelay
+		connected := false
+		for {
+			err := s.listen(ctx, func() {
+				if connected {
+					handle(LinkEvent{Op: LinksResync})
+				}
+				connected = true
+				delay = minReconnectDelay
+			}, handle)
+			if ctx.Err() != nil {
+				return
+			}
+			onError(err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, maxReconnectDelay)
+		}
+	}()
+
+	return sub
+}
+
+// listen подписывается на канал и доставляет события, пока соединение не
+// оборвётся. onListen вызывается, когда LISTEN выполнен.
+func (s *PostgresStorage) listen(ctx context.Context, onListen func(), handle func(LinkEvent)) error {
+	conn, err := pgx.Connect(ctx, s.dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect for link events: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "listen "+linkChannel); err != nil {
+		return fmt.Errorf("failed to listen for link events: %w", err)
+	}
+	onListen()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("link events connection lost: %w", err)
+		}
+
+		var ev LinkEvent
+		if err := json.Unmarshal([]byte(n.Payload), &ev); err != nil || ev.Origin == s.instance {
+			continue
+		}
+		handle(ev)
+	}
+}
+
+// Close прекращает подписку и закрывает соединение.
+func (sub *LinkSubscription) Close() {
+	sub.cancel()
+	<-sub.done
+}
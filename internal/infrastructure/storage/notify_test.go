@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeLinkEvent_SplitsLargeEvents(t *testing.T) {
+	hashes := make([]string, 2000)
+	for i := range hashes {
+		hashes[i] = fmt.Sprintf("hash%06d", i)
+	}
+
+	payloads := encodeLinkEvent(LinkEvent{Op: LinkDeleted, Hashes: hashes, Origin: "a"})
+	require.Greater(t, len(payloads), 1)
+
+	var got []string
+	for _, p := range payloads {
+		assert.LessOrEqual(t, len(p), 8000, "payload must fit into pg_notify")
+
+		var ev LinkEvent
+		require.NoError(t, json.Unmarshal([]byte(p), &ev))
+		assert.Equal(t, LinkDeleted, ev.Op)
+		assert.Equal(t, "a", ev.Origin)
+		got = append(got, ev.Hashes...)
+	}
+	assert.Equal(t, hashes, got)
+}
+
+func TestEncodeLinkEvent_WithoutHashes(t *testing.T) {
+	payloads := encodeLinkEvent(LinkEvent{Op: LinksPurged})
+	require.Len(t, payloads, 1)
+
+	var ev LinkEvent
+	require.NoError(t, json.Unmarshal([]byte(payloads[0]), &ev))
+	assert.Equal(t, LinksPurged, ev.Op)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"bytes"
+	"container/heap"
+	"slices"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+func compareIDs(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// inPage сообщает, подходит ли запись под условия страницы без учёта Limit.
+func inPage(u *model.URL, q model.URLPageQuery) bool {
+	if u.IsDeleted || compareIDs(u.ID, q.After) <= 0 {
+		return false
+	}
+	return q.UserID == nil || (u.UserID != nil && *u.UserID == *q.UserID)
+}
+
+// pageHeap держит q.Limit наименьших ID; в вершине — наибольший из них.
+type pageHeap []*model.URL
+
+func (h pageHeap) Len() int           { return len(h) }
+func (h pageHeap) Less(i, j int) bool { return compareIDs(h[i].ID, h[j].ID) > 0 }
+func (h pageHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *pageHeap) Push(x any)        { *h = append(*h, x.(*model.URL)) }
+func (h *pageHeap) Pop() any {
+	old := *h
+	u := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return u
+}
+
+// selectPage выбирает страницу из неупорядоченного набора за один проход,
+// держа в памяти не больше q.Limit записей.
+func selectPage(urls func(yield func(*model.URL) bool), q model.URLPageQuery) []*model.URL {
+	h := make(pageHeap, 0, q.Limit)
+	for u := range urls {
+		if !inPage(u, q) {
+			continue
+		}
+		if h.Len() < q.Limit {
+			heap.Push(&h, u)
+		} else if compareIDs(u.ID, h[0].ID) < 0 {
+			h[0] = u
+			heap.Fix(&h, 0)
+		}
+	}
+
+	page := []*model.URL(h)
+	slices.SortFunc(page, func(a, b *model.URL) int { return compareIDs(a.ID, b.ID) })
+	return page
+}
+
+// insertID вставляет id в отсортированный order. ID версии 7 растут со
+// временем, поэтому обычно достаточно append.
+func insertID(order []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	if n := len(order); n == 0 || compareIDs(order[n-1], id) < 0 {
+		return append(order, id)
+	}
+	i, _ := slices.BinarySearchFunc(order, id, compareIDs)
+	return slices.Insert(order, i, id)
+}
+
+func removeID(order []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	if i, found := slices.BinarySearchFunc(order, id, compareIDs); found {
+		return slices.Delete(order, i, i+1)
+	}
+	return order
+}
+
+func countClicks(clicks map[uuid.UUID][]*model.Click, urlIDs []uuid.UUID) map[uuid.UUID]int {
+	counts := make(map[uuid.UUID]int, len(urlIDs))
+	for _, id := range urlIDs {
+		if n := len(clicks[id]); n > 0 {
+			counts[id] = n
+		}
+	}
+	return counts
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pager interface {
+	Page(q model.URLPageQuery) []*model.URL
+}
+
+// collectPages проходит все страницы по курсору и возвращает коды по порядку.
+func collectPages(s pager, userID *uuid.UUID, limit int) []string {
+	var hashes []string
+	q := model.URLPageQuery{UserID: userID, Limit: limit}
+	for {
+		page := s.Page(q)
+		if len(page) == 0 {
+			return hashes
+		}
+		for _, u := range page {
+			hashes = append(hashes, u.Hash)
+		}
+		q.After = page[len(page)-1].ID
+	}
+}
+
+func TestPage_WalksAllInIDOrder(t *testing.T) {
+	owner, stranger := uuid.New(), uuid.New()
+
+	mem := NewInMemoryStorage()
+	file := openFileStorage(t, filepath.Join(t.TempDir(), "db.json"), WithCompaction(0, 0))
+	defer file.Close()
+
+	for i := range 25 {
+		userID := &owner
+		if i%5 == 0 {
+			userID = &stranger
+		}
+		u := newTestURL(t, fmt.Sprintf("https://example.com/%d", i), fmt.Sprintf("h%d", i), userID)
+		require.NoError(t, mem.Put(u))
+		require.NoError(t, file.Put(u))
+	}
+	require.True(t, mem.Update("h1", func(u *model.URL) bool {
+		u.IsDeleted = true
+		return true
+	}))
+	require.NoError(t, file.MarkDeleted(map[uuid.UUID][]string{owner: []string{"h1"}}))
+
+	all := collectPages(mem, nil, 7)
+	assert.Len(t, all, 24)
+	assert.NotContains(t, all, "h1")
+	assert.Equal(t, all, collectPages(file, nil, 7))
+
+	own := collectPages(mem, &owner, 3)
+	assert.Len(t, own, 19)
+	assert.Equal(t, own, collectPages(file, &owner, 3))
+}
+
+func TestFileStorage_IndexesSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+	s := openFileStorage(t, path, WithCompaction(0, 0))
+
+	var ids []uuid.UUID
+	for i := range 10 {
+		u := newTestURL(t, fmt.Sprintf("https://example.com/%d", i), fmt.Sprintf("h%d", i), nil)
+		require.NoError(t, s.Put(u))
+		ids = append(ids, u.ID)
+	}
+	require.NoError(t, s.AppendClicks([]*model.Click{{URLID: ids[2]}, {URLID: ids[2]}, {URLID: ids[5]}}))
+	want := collectPages(s, nil, 4)
+	require.NoError(t, s.Close())
+
+	s = openFileStorage(t, path, WithCompaction(0, 0))
+	defer s.Close()
+
+	assert.Equal(t, want, collectPages(s, nil, 4))
+	assert.Equal(t, map[uuid.UUID]int{ids[2]: 2, ids[5]: 1}, s.ClickCounts(ids))
+	assert.Len(t, s.Clicks(ids[2]), 2)
+}
@@ -5,12 +5,16 @@ import (
 	"time"
 
 	_ "github.com/amacneil/dbmate/v2/pkg/driver/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type PostgresStorage struct {
 	dsn  string
 	pool *pgxpool.Pool
+	// instance отличает события этого экземпляра сервиса от чужих.
+	instance string
 }
 
 func (s *PostgresStorage) Pool() *pgxpool.Pool {
@@ -25,11 +29,21 @@ func (s *PostgresStorage) Close() {
 	s.pool.Close()
 }
 
-func NewPostgresStorage(dsn string) (*PostgresStorage, error) {
+type PostgresOption func(*pgxpool.Config)
+
+// WithQueryTracer подключает трассировку запросов ко всем соединениям пула.
+func WithQueryTracer(t pgx.QueryTracer) PostgresOption {
+	return func(c *pgxpool.Config) { c.ConnConfig.Tracer = t }
+}
+
+func NewPostgresStorage(dsn string, opts ...PostgresOption) (*PostgresStorage, error) {
 	cfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, err
 	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -44,5 +58,5 @@ func NewPostgresStorage(dsn string) (*PostgresStorage, error) {
 		return nil, err
 	}
 
-	return &PostgresStorage{dsn: dsn, pool: pool}, nil
+	return &PostgresStorage{dsn: dsn, pool: pool, instance: uuid.NewString()}, nil
 }
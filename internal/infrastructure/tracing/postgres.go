@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const pgxTracerName = "github.com/amberdance/url-shortener/internal/infrastructure/tracing/pgx"
+
+// QueryTracer создаёт span на каждый запрос pgx с текстом SQL в
+// db.query.text. Аргументы запроса в span не попадают.
+type QueryTracer struct {
+	tracer trace.Tracer
+}
+
+var (
+	_ pgx.QueryTracer    = QueryTracer{}
+	_ pgx.BatchTracer    = QueryTracer{}
+	_ pgx.CopyFromTracer = QueryTracer{}
+)
+
+func NewQueryTracer(tp trace.TracerProvider) QueryTracer {
+	return QueryTracer{tracer: tp.Tracer(pgxTracerName)}
+}
+
+func (t QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	op := operation(data.SQL)
+	ctx, _ = t.startDBSpan(ctx, "postgres "+op, semconv.DBOperationName(op), semconv.DBQueryText(data.SQL))
+	return ctx
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	endDBSpan(ctx, data.Err)
+}
+
+func (t QueryTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	ctx, _ = t.startDBSpan(ctx, "postgres batch", semconv.DBOperationBatchSize(data.Batch.Len()))
+	return ctx
+}
+
+func (t QueryTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	op := operation(data.SQL)
+	_, span := t.startDBSpan(ctx, "postgres "+op, semconv.DBOperationName(op), semconv.DBQueryText(data.SQL))
+	telemetry.EndSpan(span, domainError(data.Err))
+}
+
+func (QueryTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	endDBSpan(ctx, data.Err)
+}
+
+func (t QueryTracer) TraceCopyFromStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	ctx, _ = t.startDBSpan(ctx, "postgres COPY",
+		semconv.DBOperationName("COPY"),
+		semconv.DBCollectionName(data.TableName.Sanitize()),
+	)
+	return ctx
+}
+
+func (QueryTracer) TraceCopyFromEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromEndData) {
+	endDBSpan(ctx, data.Err)
+}
+
+func (t QueryTracer) startDBSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, semconv.DBSystemNamePostgreSQL)...),
+	)
+}
+
+func endDBSpan(ctx context.Context, err error) {
+	telemetry.EndSpan(trace.SpanFromContext(ctx), domainError(err))
+}
+
+// domainError переводит пустую выборку pgx в errs.NotFoundError: для
+// запроса это штатный исход, а не сбой базы.
+func domainError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errs.NotFoundError(err.Error())
+	}
+	return err
+}
+
+// operation возвращает первое слово запроса: SELECT, INSERT, WITH и т.п.
+func operation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,167 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const repositoryTracerName = "github.com/amberdance/url-shortener/internal/infrastructure/tracing/repository"
+
+var backendKey = attribute.Key("repository.backend")
+
+// URLRepository оборачивает next так, что каждая операция порождает span
+// с меткой backend. Запросы PostgreSQL внутри операции становятся его
+// дочерними span'ами через QueryTracer.
+func URLRepository(next repository.URLRepository, backend string, tp trace.TracerProvider) repository.URLRepository {
+	return &urlRepository{next: next, spans: newRepoSpans(tp, backend)}
+}
+
+// ClickRepository оборачивает next так же, как URLRepository.
+func ClickRepository(next repository.ClickRepository, backend string, tp trace.TracerProvider) repository.ClickRepository {
+	return &clickRepository{next: next, spans: newRepoSpans(tp, backend)}
+}
+
+type repoSpans struct {
+	tracer  trace.Tracer
+	backend string
+}
+
+func newRepoSpans(tp trace.TracerProvider, backend string) repoSpans {
+	return repoSpans{tracer: tp.Tracer(repositoryTracerName), backend: backend}
+}
+
+func (s repoSpans) start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "repository."+name,
+		trace.WithAttributes(backendKey.String(s.backend)),
+	)
+}
+
+type urlRepository struct {
+	next  repository.URLRepository
+	spans repoSpans
+}
+
+func (r *urlRepository) Create(ctx context.Context, url *model.URL) error {
+	ctx, span := r.spans.start(ctx, "URL.Create")
+	err := r.next.Create(ctx, url)
+	telemetry.EndSpan(span, err)
+	return err
+}
+
+func (r *urlRepository) CreateBatch(ctx context.Context, urls []*model.URL) ([]error, error) {
+	ctx, span := r.spans.start(ctx, "URL.CreateBatch")
+	span.SetAttributes(attribute.Int("repository.batch_size", len(urls)))
+	itemErrs, err := r.next.CreateBatch(ctx, urls)
+	telemetry.EndSpan(span, err)
+	return itemErrs, err
+}
+
+func (r *urlRepository) FindByHash(ctx context.Context, hash string) (*model.URL, error) {
+	ctx, span := r.spans.start(ctx, "URL.FindByHash")
+	m, err := r.next.FindByHash(ctx, hash)
+	telemetry.EndSpan(span, err)
+	return m, err
+}
+
+func (r *urlRepository) FindByOriginalURL(ctx context.Context, originalURL string) (*model.URL, error) {
+	ctx, span := r.spans.start(ctx, "URL.FindByOriginalURL")
+	m, err := r.next.FindByOriginalURL(ctx, originalURL)
+	telemetry.EndSpan(span, err)
+	return m, err
+}
+
+func (r *urlRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*model.URL, error) {
+	ctx, span := r.spans.start(ctx, "URL.FindByUserID")
+	urls, err := r.next.FindByUserID(ctx, userID)
+	telemetry.EndSpan(span, err)
+	return urls, err
+}
+
+func (r *urlRepository) FindPage(ctx context.Context, q model.URLPageQuery) ([]*model.URL, error) {
+	ctx, span := r.spans.start(ctx, "URL.FindPage")
+	urls, err := r.next.FindPage(ctx, q)
+	telemetry.EndSpan(span, err)
+	return urls, err
+}
+
+func (r *urlRepository) DeleteBatch(ctx context.Context, byUser map[uuid.UUID][]string) error {
+	ctx, span := r.spans.start(ctx, "URL.DeleteBatch")
+	err := r.next.DeleteBatch(ctx, byUser)
+	telemetry.EndSpan(span, err)
+	return err
+}
+
+func (r *urlRepository) Update(ctx context.Context, url *model.URL, rev *model.URLRevision) error {
+	ctx, span := r.spans.start(ctx, "URL.Update")
+	err := r.next.Update(ctx, url, rev)
+	telemetry.EndSpan(span, err)
+	return err
+}
+
+func (r *urlRepository) FindRevisions(ctx context.Context, urlID uuid.UUID) ([]*model.URLRevision, error) {
+	ctx, span := r.spans.start(ctx, "URL.FindRevisions")
+	revs, err := r.next.FindRevisions(ctx, urlID)
+	telemetry.EndSpan(span, err)
+	return revs, err
+}
+
+func (r *urlRepository) CountURLs(ctx context.Context) (int, error) {
+	ctx, span := r.spans.start(ctx, "URL.CountURLs")
+	n, err := r.next.CountURLs(ctx)
+	telemetry.EndSpan(span, err)
+	return n, err
+}
+
+func (r *urlRepository) CountUsers(ctx context.Context) (int, error) {
+	ctx, span := r.spans.start(ctx, "URL.CountUsers")
+	n, err := r.next.CountUsers(ctx)
+	telemetry.EndSpan(span, err)
+	return n, err
+}
+
+func (r *urlRepository) RegisterClick(ctx context.Context, hash string) (bool, error) {
+	ctx, span := r.spans.start(ctx, "URL.RegisterClick")
+	ok, err := r.next.RegisterClick(ctx, hash)
+	telemetry.EndSpan(span, err)
+	return ok, err
+}
+
+func (r *urlRepository) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := r.spans.start(ctx, "URL.PurgeExpired")
+	n, err := r.next.PurgeExpired(ctx, now)
+	telemetry.EndSpan(span, err)
+	return n, err
+}
+
+type clickRepository struct {
+	next  repository.ClickRepository
+	spans repoSpans
+}
+
+func (r *clickRepository) SaveBatch(ctx context.Context, clicks []*model.Click) error {
+	ctx, span := r.spans.start(ctx, "Click.SaveBatch")
+	err := r.next.SaveBatch(ctx, clicks)
+	telemetry.EndSpan(span, err)
+	return err
+}
+
+func (r *clickRepository) Stats(ctx context.Context, q model.ClickStatsQuery) (*model.ClickStats, error) {
+	ctx, span := r.spans.start(ctx, "Click.Stats")
+	stats, err := r.next.Stats(ctx, q)
+	telemetry.EndSpan(span, err)
+	return stats, err
+}
+
+func (r *clickRepository) CountByURL(ctx context.Context, urlIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	ctx, span := r.spans.start(ctx, "Click.CountByURL")
+	counts, err := r.next.CountByURL(ctx, urlIDs)
+	telemetry.EndSpan(span, err)
+	return counts, err
+}
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/amberdance/url-shortener/internal/app/telemetry"
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestURLRepository_SpanPerCall(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	repo := URLRepository(url.NewInMemoryURLRepository(storage.NewInMemoryStorage()), "memory", tp)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "request")
+	_, err := repo.RegisterClick(ctx, "missing")
+	parent.End()
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "repository.URL.RegisterClick", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code, "«не найдено» не считается сбоем")
+	assert.Len(t, spans[0].Events(), 1)
+	assert.Contains(t, spans[0].Attributes(), telemetry.ErrorIDKey.String("not_found"))
+}
+
+func TestSetup_FileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	tp, stop, err := Setup(context.Background(), ExporterFile, "", path)
+	require.NoError(t, err)
+	assert.NotSame(t, tp, otel.GetTracerProvider(), "Setup не трогает глобальный провайдер")
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "work")
+	assert.NotEmpty(t, TraceID(ctx))
+	span.End()
+	require.NoError(t, stop(context.Background()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"work"`)
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, _, err := Setup(context.Background(), "jaeger", "", "")
+	assert.Error(t, err)
+}
+
+func TestOperation(t *testing.T) {
+	assert.Equal(t, "SELECT", operation("  select id from urls"))
+	assert.Equal(t, "QUERY", operation(""))
+}
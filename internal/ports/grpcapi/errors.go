@@ -0,0 +1,49 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/amberdance/url-shortener/internal/app/worker"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus переводит доменные ошибки в коды gRPC так же, как
+// helpers.HandleError переводит их в HTTP-статусы.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var code codes.Code
+
+	switch e := err.(type) {
+	case errs.NotFoundError:
+		code = codes.NotFound
+	case errs.InvalidArgumentError, errs.ValidationError:
+		code = codes.InvalidArgument
+	case errs.InternalError:
+		code = codes.Internal
+	case errs.UnauthorizedError:
+		code = codes.Unauthenticated
+	case errs.DuplicateEntryError, errs.AliasTakenError, errs.HashConflictError:
+		code = codes.AlreadyExists
+	case errs.GoneError:
+		code = codes.FailedPrecondition
+	default:
+		switch {
+		case errors.Is(e, context.DeadlineExceeded):
+			code = codes.DeadlineExceeded
+		case errors.Is(e, context.Canceled):
+			code = codes.Canceled
+		case errors.Is(e, worker.ErrDeleterStopped):
+			code = codes.Unavailable
+		default:
+			code = codes.Internal
+		}
+	}
+
+	return status.Error(code, err.Error())
+}
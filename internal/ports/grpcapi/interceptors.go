@@ -0,0 +1,94 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
+	"github.com/amberdance/url-shortener/internal/infrastructure/tracing"
+	"github.com/google/uuid"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const AuthMetadataKey = "auth_token"
+
+const tracerName = "github.com/amberdance/url-shortener/internal/ports/grpcapi"
+
+// tracingInterceptor — аналог middleware.TracingMiddleware: открывает
+// серверный span на каждый вызов, продолжая трассу из метаданных.
+func tracingInterceptor(tp trace.TracerProvider) grpc.UnaryServerInterceptor {
+	tracer := tp.Tracer(tracerName)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = tracing.Propagator.Extract(ctx, metadataCarrier(md))
+
+		ctx, span := tracer.Start(ctx, info.FullMethod, trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		if st := status.Convert(err); st.Code() == codes.Internal || st.Code() == codes.Unknown {
+			span.SetStatus(otelcodes.Error, st.Message())
+		}
+		return resp, err
+	}
+}
+
+// metadataCarrier читает заголовки трассы из метаданных gRPC.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) { metadata.MD(c).Set(key, value) }
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// authInterceptor — аналог middleware.AuthMiddleware: достаёт токен из
+// метаданных запроса, а при его отсутствии выпускает новый и отдаёт его
+// клиенту в заголовке ответа.
+func authInterceptor(tm *auth.TokenManager) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(AuthMetadataKey); len(values) > 0 {
+				userID, err := tm.Parse(values[0])
+				if err == nil {
+					return handler(auth.WithUserID(ctx, userID), req)
+				}
+
+				var unauthorized errs.UnauthorizedError
+				if errors.As(err, &unauthorized) {
+					return nil, toStatus(unauthorized)
+				}
+			}
+		}
+
+		userID := uuid.New()
+		token, err := tm.Issue(userID)
+		if err != nil {
+			return nil, toStatus(errs.InternalError("Не удалось выпустить токен"))
+		}
+
+		if err := grpc.SetHeader(ctx, metadata.Pairs(AuthMetadataKey, token)); err != nil {
+			return nil, toStatus(errs.InternalError(err.Error()))
+		}
+
+		return handler(auth.WithIssuedUserID(ctx, userID), req)
+	}
+}
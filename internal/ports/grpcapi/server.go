@@ -0,0 +1,66 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/amberdance/url-shortener/internal/app"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/ports/grpcapi/pb"
+	"google.golang.org/grpc"
+)
+
+type Server struct {
+	address    string
+	grpcServer *grpc.Server
+	logger     shared.Logger
+}
+
+func NewServer(a *app.App) *Server {
+	grpcSrv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			tracingInterceptor(a.TracerProvider()),
+			authInterceptor(a.Container().TokenManager),
+		),
+	)
+
+	pb.RegisterShortenerServer(grpcSrv, NewShortenerService(
+		a.Config().BaseURL,
+		a.Container().UseCases.URL,
+		a.Logger(),
+	))
+
+	return &Server{address: a.Config().GRPCAddress, grpcServer: grpcSrv, logger: a.Logger()}
+}
+
+// Run обслуживает gRPC-запросы, пока не будет отменён ctx, после чего
+// дожидается завершения активных вызовов.
+func (s *Server) Run(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info(fmt.Sprintf("gRPC server is running on %s", s.address))
+
+	stopped := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		s.logger.Info("Context cancelled, shutting down gRPC server")
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	if err := s.grpcServer.Serve(lis); err != nil {
+		return err
+	}
+	<-stopped
+	s.logger.Info("gRPC server stopped gracefully")
+	return nil
+}
+
+func (s *Server) Stop() {
+	s.logger.Info("Stopping gRPC server manually")
+	s.grpcServer.GracefulStop()
+}
@@ -0,0 +1,200 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/usecase"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
+	"github.com/amberdance/url-shortener/internal/ports/grpcapi/pb"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ShortenerService struct {
+	pb.UnimplementedShortenerServer
+
+	baseURL  string
+	usecases usecase.URLUseCases
+	logger   shared.Logger
+}
+
+func NewShortenerService(baseURL string, uc usecase.URLUseCases, l shared.Logger) *ShortenerService {
+	return &ShortenerService{baseURL: baseURL, usecases: uc, logger: l}
+}
+
+func (s *ShortenerService) Shorten(ctx context.Context, req *pb.ShortenRequest) (*pb.ShortenResponse, error) {
+	if req.GetUrl() == "" {
+		return nil, toStatus(errs.ValidationError("Не передан URL"))
+	}
+
+	m, err := s.usecases.Create.Run(ctx, command.CreateURLEntryCommand{
+		OriginalURL:   req.GetUrl(),
+		CorrelationID: req.CorrelationId,
+		UserID:        userIDFromContext(ctx),
+		Alias:         req.GetAlias(),
+		ExpiresAt:     expiresAt(req.GetExpiresAt()),
+		MaxClicks:     maxClicks(req.MaxClicks),
+		RedirectCode:  int(req.GetRedirectCode()),
+		NoIndex:       req.GetNoindex(),
+	})
+	if err != nil {
+		var conflictErr errs.DuplicateEntryError
+		if errors.As(err, &conflictErr) && m != nil {
+			st, detailsErr := status.New(codes.AlreadyExists, conflictErr.Error()).
+				WithDetails(&pb.ShortenResponse{ShortUrl: s.formatFullURL(m.Hash)})
+			if detailsErr != nil {
+				return nil, toStatus(conflictErr)
+			}
+			return nil, st.Err()
+		}
+		return nil, s.handleError(err)
+	}
+
+	return &pb.ShortenResponse{ShortUrl: s.formatFullURL(m.Hash)}, nil
+}
+
+func (s *ShortenerService) ShortenBatch(ctx context.Context, req *pb.ShortenBatchRequest) (*pb.ShortenBatchResponse, error) {
+	if len(req.GetItems()) == 0 {
+		return nil, toStatus(errs.ValidationError("Не передано ни одного url"))
+	}
+
+	cmd := command.CreateBatchURLEntryCommand{
+		Entries: make([]command.CreateURLEntryCommand, 0, len(req.GetItems())),
+		UserID:  userIDFromContext(ctx),
+	}
+
+	for _, item := range req.GetItems() {
+		correlationID := item.GetCorrelationId()
+		cmd.Entries = append(cmd.Entries, command.CreateURLEntryCommand{
+			OriginalURL:   item.GetOriginalUrl(),
+			CorrelationID: &correlationID,
+			Alias:         item.GetAlias(),
+			ExpiresAt:     expiresAt(item.GetExpiresAt()),
+			MaxClicks:     maxClicks(item.MaxClicks),
+			RedirectCode:  int(item.GetRedirectCode()),
+			NoIndex:       item.GetNoindex(),
+		})
+	}
+
+	results, err := s.usecases.CreateBatch.Run(ctx, cmd)
+	if err != nil {
+		return nil, s.handleError(err)
+	}
+
+	res := &pb.ShortenBatchResponse{Items: make([]*pb.ShortenBatchResponse_Item, 0, len(results))}
+	for _, r := range results {
+		item := &pb.ShortenBatchResponse_Item{
+			CorrelationId: *r.CorrelationID,
+			Status:        string(r.Status),
+		}
+		if r.URL != nil {
+			item.ShortUrl = s.formatFullURL(r.URL.Hash)
+		} else {
+			item.ErrorId = errs.ID(r.Err)
+			item.ErrorMessage = r.Err.Error()
+		}
+		res.Items = append(res.Items, item)
+	}
+
+	return res, nil
+}
+
+func (s *ShortenerService) Resolve(ctx context.Context, req *pb.ResolveRequest) (*pb.ResolveResponse, error) {
+	if req.GetHash() == "" {
+		return nil, toStatus(errs.ValidationError("Не передана ссылка"))
+	}
+
+	m, err := s.usecases.GetByURL.Run(ctx, command.GetURLByHashCommand{Hash: req.GetHash()})
+	if err != nil {
+		var goneErr errs.GoneError
+		if errors.As(err, &goneErr) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, toStatus(err)
+		}
+		return nil, toStatus(errs.NotFoundError("Не найден ресурс"))
+	}
+
+	return &pb.ResolveResponse{OriginalUrl: m.OriginalURL}, nil
+}
+
+func (s *ShortenerService) ListUserURLs(ctx context.Context, _ *emptypb.Empty) (*pb.ListUserURLsResponse, error) {
+	userID, ok := auth.AuthenticatedUserID(ctx)
+	if !ok {
+		return nil, toStatus(errs.UnauthorizedError("Пользователь не авторизован"))
+	}
+
+	urls, err := s.usecases.GetByUserID.Run(ctx, command.GetURLsByUserIDCommand{UserID: userID})
+	if err != nil {
+		return nil, s.handleError(err)
+	}
+
+	res := &pb.ListUserURLsResponse{Items: make([]*pb.ListUserURLsResponse_Item, 0, len(urls))}
+	for _, u := range urls {
+		res.Items = append(res.Items, &pb.ListUserURLsResponse_Item{
+			ShortUrl:    s.formatFullURL(u.Hash),
+			OriginalUrl: u.OriginalURL,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *ShortenerService) DeleteUserURLs(ctx context.Context, req *pb.DeleteUserURLsRequest) (*emptypb.Empty, error) {
+	userID, ok := auth.AuthenticatedUserID(ctx)
+	if !ok {
+		return nil, toStatus(errs.UnauthorizedError("Пользователь не авторизован"))
+	}
+
+	err := s.usecases.DeleteBatch.Run(ctx, command.DeleteURLsCommand{
+		UserID: userID,
+		Hashes: req.GetHashes(),
+	})
+	if err != nil {
+		return nil, s.handleError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *ShortenerService) handleError(err error) error {
+	st := toStatus(err)
+	if status.Code(st) == codes.Internal {
+		s.logger.Error(err.Error())
+	}
+	return st
+}
+
+func (s *ShortenerService) formatFullURL(hash string) string {
+	return s.baseURL + hash
+}
+
+func expiresAt(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func maxClicks(n *int32) *int {
+	if n == nil {
+		return nil
+	}
+	v := int(*n)
+	return &v
+}
+
+func userIDFromContext(ctx context.Context) *uuid.UUID {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return &userID
+}
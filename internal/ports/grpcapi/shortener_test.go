@@ -0,0 +1,185 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/amberdance/url-shortener/internal/app/usecase"
+	"github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/app/worker"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
+	"github.com/amberdance/url-shortener/internal/infrastructure/hashgen"
+	infr "github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/amberdance/url-shortener/internal/ports/grpcapi/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const testHost = "http://127.0.0.1:9999/"
+
+var testAliasPolicy = model.AliasPolicy{
+	Charset:   "abcdefghijklmnopqrstuvwxyz0123456789-_",
+	MinLength: 3,
+	MaxLength: 32,
+	Reserved:  []string{"api"},
+}
+
+type mockLogger struct{}
+
+func (mockLogger) Debug(_ string, _ ...any)                           {}
+func (mockLogger) Info(_ string, _ ...any)                            {}
+func (mockLogger) Error(_ string, _ ...any)                           {}
+func (mockLogger) DebugContext(_ context.Context, _ string, _ ...any) {}
+func (mockLogger) InfoContext(_ context.Context, _ string, _ ...any)  {}
+func (mockLogger) ErrorContext(_ context.Context, _ string, _ ...any) {}
+func (mockLogger) With(_ ...any) shared.Logger                        { return mockLogger{} }
+func (mockLogger) Close() error                                       { return nil }
+
+func setupClient(t *testing.T) pb.ShortenerClient {
+	repo := infr.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	deleter := worker.NewURLDeleter(repo, mockLogger{})
+	deleter.Start()
+	t.Cleanup(func() { _ = deleter.Stop(context.Background()) })
+
+	useCases := usecase.URLUseCases{
+		Create:      url.NewCreateURLUseCase(repo, shared.SystemClock{}, testAliasPolicy, hashgen.NewRandomGenerator(8)),
+		CreateBatch: url.NewBatchCreateURLUseCase(repo, shared.SystemClock{}, testAliasPolicy, hashgen.NewRandomGenerator(8)),
+		GetByURL:    url.NewGetByHashUseCase(repo, shared.SystemClock{}),
+		GetByUserID: url.NewGetByUserIDUseCase(repo),
+		DeleteBatch: url.NewDeleteBatchUseCase(deleter),
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(authInterceptor(auth.NewTokenManager("secret"))))
+	pb.RegisterShortenerServer(srv, NewShortenerService(testHost, useCases, mockLogger{}))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pb.NewShortenerClient(conn)
+}
+
+func TestShortenAndResolve(t *testing.T) {
+	client := setupClient(t)
+	ctx := context.Background()
+
+	var header metadata.MD
+	res, err := client.Shorten(ctx, &pb.ShortenRequest{Url: "https://hard2code.ru"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(res.GetShortUrl(), testHost))
+	assert.NotEmpty(t, header.Get(AuthMetadataKey))
+
+	resolved, err := client.Resolve(ctx, &pb.ResolveRequest{Hash: strings.TrimPrefix(res.GetShortUrl(), testHost)})
+	require.NoError(t, err)
+	assert.Equal(t, "https://hard2code.ru", resolved.GetOriginalUrl())
+}
+
+func TestShorten_AlreadyExists(t *testing.T) {
+	client := setupClient(t)
+	ctx := context.Background()
+
+	first, err := client.Shorten(ctx, &pb.ShortenRequest{Url: "https://hard2code.ru"})
+	require.NoError(t, err)
+
+	_, err = client.Shorten(ctx, &pb.ShortenRequest{Url: "https://hard2code.ru"})
+	st := status.Convert(err)
+	assert.Equal(t, codes.AlreadyExists, st.Code())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, first.GetShortUrl(), st.Details()[0].(*pb.ShortenResponse).GetShortUrl())
+}
+
+func TestShorten_Alias(t *testing.T) {
+	client := setupClient(t)
+	ctx := context.Background()
+
+	alias := "my-link"
+	res, err := client.Shorten(ctx, &pb.ShortenRequest{Url: "https://hard2code.ru", Alias: &alias})
+	require.NoError(t, err)
+	assert.Equal(t, testHost+alias, res.GetShortUrl())
+
+	_, err = client.Shorten(ctx, &pb.ShortenRequest{Url: "https://example.com", Alias: &alias})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	reserved := "api"
+	_, err = client.Shorten(ctx, &pb.ShortenRequest{Url: "https://example.com", Alias: &reserved})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestShortenBatch_PartialSuccess(t *testing.T) {
+	client := setupClient(t)
+	ctx := context.Background()
+
+	known, err := client.Shorten(ctx, &pb.ShortenRequest{Url: "https://hard2code.ru"})
+	require.NoError(t, err)
+
+	reserved := "api"
+	res, err := client.ShortenBatch(ctx, &pb.ShortenBatchRequest{Items: []*pb.ShortenBatchRequest_Item{
+		{CorrelationId: "1", OriginalUrl: "https://hard2code.ru"},
+		{CorrelationId: "2", OriginalUrl: "https://example.com"},
+		{CorrelationId: "3", OriginalUrl: "https://example.org", Alias: &reserved},
+	}})
+	require.NoError(t, err)
+	require.Len(t, res.GetItems(), 3)
+
+	assert.Equal(t, "existing", res.GetItems()[0].GetStatus())
+	assert.Equal(t, known.GetShortUrl(), res.GetItems()[0].GetShortUrl())
+	assert.Equal(t, "created", res.GetItems()[1].GetStatus())
+	assert.Equal(t, "invalid", res.GetItems()[2].GetStatus())
+	assert.Equal(t, "validation_error", res.GetItems()[2].GetErrorId())
+	assert.Empty(t, res.GetItems()[2].GetShortUrl())
+}
+
+func TestResolve_NotFound(t *testing.T) {
+	client := setupClient(t)
+
+	_, err := client.Resolve(context.Background(), &pb.ResolveRequest{Hash: "none"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestListUserURLs(t *testing.T) {
+	client := setupClient(t)
+
+	_, err := client.ListUserURLs(context.Background(), &emptypb.Empty{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	var header metadata.MD
+	_, err = client.Shorten(context.Background(), &pb.ShortenRequest{Url: "https://hard2code.ru"}, grpc.Header(&header))
+	require.NoError(t, err)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), AuthMetadataKey, header.Get(AuthMetadataKey)[0])
+	res, err := client.ListUserURLs(ctx, &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, res.GetItems(), 1)
+	assert.Equal(t, "https://hard2code.ru", res.GetItems()[0].GetOriginalUrl())
+}
+
+func TestTamperedToken(t *testing.T) {
+	client := setupClient(t)
+
+	token, err := auth.NewTokenManager("other-secret").Issue([16]byte{1})
+	require.NoError(t, err)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), AuthMetadataKey, token)
+	_, err = client.Shorten(ctx, &pb.ShortenRequest{Url: "https://hard2code.ru"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
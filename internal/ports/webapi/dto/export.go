@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type ExportedURLResponse struct {
+	Hash          string     `json:"hash"`
+	ShortURL      string     `json:"short_url"`
+	OriginalURL   string     `json:"original_url"`
+	CorrelationID string     `json:"correlation_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+	Clicks        int        `json:"clicks"`
+}
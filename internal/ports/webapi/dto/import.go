@@ -0,0 +1,26 @@
+package dto
+
+// ImportResultLine — строка ответа импорта с итогом по одной записи. Line —
+// номер строки во входных данных.
+type ImportResultLine struct {
+	Line          int             `json:"line"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	ShortURL      string          `json:"short_url,omitempty"`
+	Status        string          `json:"status"`
+	Error         *BatchItemError `json:"error,omitempty"`
+}
+
+// ImportStatusLine сообщает о ходе импорта после каждой порции записей.
+// Последняя строка ответа содержит Done или Error.
+type ImportStatusLine struct {
+	Progress ImportProgress  `json:"progress"`
+	Done     bool            `json:"done,omitempty"`
+	Error    *BatchItemError `json:"error,omitempty"`
+}
+
+type ImportProgress struct {
+	Processed int `json:"processed"`
+	Created   int `json:"created"`
+	Existing  int `json:"existing"`
+	Invalid   int `json:"invalid"`
+}
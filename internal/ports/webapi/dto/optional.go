@@ -0,0 +1,25 @@
+package dto
+
+import "encoding/json"
+
+// Optional отличает отсутствующее в JSON поле (Set == false) от явного
+// null (Set == true, Value == nil).
+type Optional[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (o *Optional[T]) UnmarshalJSON(b []byte) error {
+	o.Set = true
+	if string(b) == "null" {
+		o.Value = nil
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	o.Value = &v
+	return nil
+}
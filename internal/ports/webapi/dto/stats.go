@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type StatsResponse struct {
+	URLs  int `json:"urls"`
+	Users int `json:"users"`
+}
+
+type LinkStatsResponse struct {
+	Hash           string               `json:"hash"`
+	TotalClicks    int                  `json:"total_clicks"`
+	UniqueVisitors int                  `json:"unique_visitors"`
+	Daily          []ClickPointResponse `json:"daily"`
+	Hourly         []ClickPointResponse `json:"hourly"`
+}
+
+type ClickPointResponse struct {
+	Time   time.Time `json:"time"`
+	Clicks int       `json:"clicks"`
+}
@@ -1,19 +1,99 @@
 package dto
 
+import "time"
+
 type ShortURLRequest struct {
-	CorrelationID *string `json:"correlation_id"`
-	URL           string  `json:"url" validate:"required"`
+	CorrelationID *string    `json:"correlation_id"`
+	URL           string     `json:"url" validate:"required"`
+	Alias         string     `json:"alias,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	MaxClicks     *int       `json:"max_clicks,omitempty"`
+	RedirectCode  int        `json:"redirect_code,omitempty"`
+	NoIndex       bool       `json:"noindex,omitempty"`
 }
 type ShortURLResponse struct {
 	URL string `json:"result"`
 }
 
 type BatchShortenURLRequest struct {
-	CorrelationID string `json:"correlation_id" validate:"required"`
-	URL           string `json:"original_url" validate:"required"`
+	CorrelationID string     `json:"correlation_id" validate:"required"`
+	URL           string     `json:"original_url" validate:"required"`
+	Alias         string     `json:"alias,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	MaxClicks     *int       `json:"max_clicks,omitempty"`
+	RedirectCode  int        `json:"redirect_code,omitempty"`
+	NoIndex       bool       `json:"noindex,omitempty"`
 }
 
+// BatchShortenURLResponse — итог по одной записи пачки. Status принимает
+// значения created, existing или invalid; для invalid заполнен Error.
 type BatchShortenURLResponse struct {
-	CorrelationID string `json:"correlation_id"`
-	URL           string `json:"short_url"`
+	CorrelationID string          `json:"correlation_id"`
+	URL           string          `json:"short_url,omitempty"`
+	Status        string          `json:"status"`
+	Error         *BatchItemError `json:"error,omitempty"`
+}
+
+type BatchItemError struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+type UserURLResponse struct {
+	ShortURL    string `json:"short_url"`
+	OriginalURL string `json:"original_url"`
+}
+
+// UpdateURLRequest — тело PATCH: отсутствующие поля не меняются, null в
+// expires_at и max_clicks снимает ограничение, redirect_code 0 возвращает
+// код по умолчанию.
+type UpdateURLRequest struct {
+	OriginalURL  *string             `json:"original_url"`
+	ExpiresAt    Optional[time.Time] `json:"expires_at"`
+	MaxClicks    Optional[int]       `json:"max_clicks"`
+	RedirectCode *int                `json:"redirect_code"`
+	NoIndex      *bool               `json:"noindex"`
+}
+
+type URLResponse struct {
+	Hash         string     `json:"hash"`
+	ShortURL     string     `json:"short_url"`
+	OriginalURL  string     `json:"original_url"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	MaxClicks    *int       `json:"max_clicks,omitempty"`
+	RedirectCode int        `json:"redirect_code,omitempty"`
+	NoIndex      bool       `json:"noindex,omitempty"`
+}
+
+// URLRevisionResponse — прежнее состояние ссылки; ReplacedAt — когда оно
+// было заменено.
+type URLRevisionResponse struct {
+	ID           string     `json:"id"`
+	OriginalURL  string     `json:"original_url"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	MaxClicks    *int       `json:"max_clicks,omitempty"`
+	RedirectCode int        `json:"redirect_code,omitempty"`
+	NoIndex      bool       `json:"noindex,omitempty"`
+	ReplacedAt   time.Time  `json:"replaced_at"`
+}
+
+// URLInfoResponse — полная запись ссылки для GET /api/urls/{hash}. Status
+// принимает значения active, expired или deleted.
+type URLInfoResponse struct {
+	Hash          string     `json:"hash"`
+	ShortURL      string     `json:"short_url"`
+	OriginalURL   string     `json:"original_url"`
+	CorrelationID *string    `json:"correlation_id,omitempty"`
+	UserID        *string    `json:"user_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	MaxClicks     *int       `json:"max_clicks,omitempty"`
+	RedirectCode  int        `json:"redirect_code"`
+	NoIndex       bool       `json:"noindex"`
+	Clicks        int        `json:"clicks"`
+	Status        string     `json:"status"`
 }
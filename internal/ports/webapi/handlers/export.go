@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/helpers"
+	webmw "github.com/amberdance/url-shortener/internal/ports/webapi/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ExportHandler выгружает ссылки потоком в CSV, JSON или NDJSON.
+type ExportHandler struct {
+	baseURL string
+	usecase url.ExportURLsUseCase
+	logger  shared.Logger
+}
+
+func NewExportHandler(host string, uc url.ExportURLsUseCase, l shared.Logger) *ExportHandler {
+	return &ExportHandler{host, uc, l}
+}
+
+// UserRoutes выгружает ссылки текущего пользователя.
+func (h *ExportHandler) UserRoutes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer)
+	r.With(webmw.RequireAuthMiddleware).Get("/", func(w http.ResponseWriter, r *http.Request) {
+		h.export(w, r, command.ExportURLsCommand{UserID: userIDFromRequest(r)})
+	})
+	return r
+}
+
+// AdminRoutes выгружает ссылки всех пользователей; доступ только из
+// доверенной подсети.
+func (h *ExportHandler) AdminRoutes(trustedSubnet string) chi.Router {
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer)
+	r.Use(webmw.TrustedSubnetMiddleware(trustedSubnet))
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		h.export(w, r, command.ExportURLsCommand{})
+	})
+	return r
+}
+
+func (h *ExportHandler) export(w http.ResponseWriter, r *http.Request, cmd command.ExportURLsCommand) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+
+	newWriter, ok := exportFormats[format]
+	if !ok {
+		helpers.HandleError(w, errs.ValidationError("format должен быть csv, json или ndjson"))
+		return
+	}
+
+	ew := newWriter(w)
+	flush := http.NewResponseController(w).Flush
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", ew.contentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="urls.%s"`, format))
+		w.WriteHeader(http.StatusOK)
+		return ew.begin()
+	}
+
+	err := h.usecase.Run(r.Context(), cmd, func(page []model.ExportedURL) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		for _, u := range page {
+			if err := ew.write(h.toResponse(u)); err != nil {
+				return err
+			}
+		}
+		if err := ew.flush(); err != nil {
+			return err
+		}
+		return flush()
+	})
+
+	if err != nil {
+		if !started {
+			h.logger.ErrorContext(r.Context(), "failed to export urls", "error", err)
+			helpers.HandleError(w, errs.InternalError("Не удалось выгрузить ссылки"))
+			return
+		}
+		// Заголовки уже отправлены: клиент увидит оборванный файл.
+		h.logger.ErrorContext(r.Context(), "url export aborted", "error", err)
+		return
+	}
+
+	if !started {
+		if err := start(); err != nil {
+			return
+		}
+	}
+	if err := ew.end(); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to finish url export", "error", err)
+	}
+}
+
+func (h *ExportHandler) toResponse(u model.ExportedURL) dto.ExportedURLResponse {
+	res := dto.ExportedURLResponse{
+		Hash:        u.Hash,
+		ShortURL:    h.baseURL + u.Hash,
+		OriginalURL: u.OriginalURL,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+		Clicks:      u.TotalClicks,
+	}
+	if u.CorrelationID != nil {
+		res.CorrelationID = *u.CorrelationID
+	}
+	return res
+}
+
+// exportWriter пишет записи выгрузки в одном из форматов.
+type exportWriter interface {
+	contentType() string
+	begin() error
+	write(u dto.ExportedURLResponse) error
+	flush() error
+	end() error
+}
+
+var exportFormats = map[string]func(io.Writer) exportWriter{
+	"csv":    func(w io.Writer) exportWriter { return &csvExportWriter{w: csv.NewWriter(w)} },
+	"json":   func(w io.Writer) exportWriter { return &jsonExportWriter{w: w, enc: json.NewEncoder(w)} },
+	"ndjson": func(w io.Writer) exportWriter { return &ndjsonExportWriter{enc: json.NewEncoder(w)} },
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvExportWriter) contentType() string { return "text/csv; charset=utf-8" }
+
+func (c *csvExportWriter) begin() error {
+	return c.w.Write([]string{"hash", "short_url", "original_url", "correlation_id", "created_at", "updated_at", "clicks"})
+}
+
+func (c *csvExportWriter) write(u dto.ExportedURLResponse) error {
+	updatedAt := ""
+	if u.UpdatedAt != nil {
+		updatedAt = u.UpdatedAt.Format(time.RFC3339)
+	}
+	return c.w.Write([]string{
+		u.Hash,
+		u.ShortURL,
+		u.OriginalURL,
+		u.CorrelationID,
+		u.CreatedAt.Format(time.RFC3339),
+		updatedAt,
+		strconv.Itoa(u.Clicks),
+	})
+}
+
+func (c *csvExportWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvExportWriter) end() error { return c.flush() }
+
+// jsonExportWriter пишет один JSON-массив, не собирая его в памяти.
+type jsonExportWriter struct {
+	w     io.Writer
+	enc   *json.Encoder
+	count int
+}
+
+func (j *jsonExportWriter) contentType() string { return "application/json" }
+
+func (j *jsonExportWriter) begin() error {
+	_, err := io.WriteString(j.w, "[")
+	return err
+}
+
+func (j *jsonExportWriter) write(u dto.ExportedURLResponse) error {
+	if j.count > 0 {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	j.count++
+	return j.enc.Encode(u)
+}
+
+func (j *jsonExportWriter) flush() error { return nil }
+
+func (j *jsonExportWriter) end() error {
+	_, err := io.WriteString(j.w, "]\n")
+	return err
+}
+
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonExportWriter) contentType() string { return "application/x-ndjson" }
+
+func (n *ndjsonExportWriter) begin() error { return nil }
+
+func (n *ndjsonExportWriter) write(u dto.ExportedURLResponse) error { return n.enc.Encode(u) }
+
+func (n *ndjsonExportWriter) flush() error { return nil }
+
+func (n *ndjsonExportWriter) end() error { return nil }
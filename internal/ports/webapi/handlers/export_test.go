@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository/click"
+	infr "github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
+	webmw "github.com/amberdance/url-shortener/internal/ports/webapi/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupExport заполняет хранилище n ссылками владельца owner и одной чужой
+// ссылкой с двумя переходами.
+func setupExport(t *testing.T, owner uuid.UUID, n int) *ExportHandler {
+	st := storage.NewInMemoryStorage()
+	repo = infr.NewInMemoryURLRepository(st)
+	clicks = click.NewInMemoryClickRepository(st)
+	ctx := context.Background()
+
+	for i := range n {
+		m, err := model.NewURL(fmt.Sprintf("https://example.com/%d", i), fmt.Sprintf("h%d", i), nil, &owner, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, m))
+	}
+
+	stranger := uuid.New()
+	other, err := model.NewURL("https://other.example", "other", nil, &stranger, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, other))
+	require.NoError(t, clicks.SaveBatch(ctx, []*model.Click{{URLID: other.ID}, {URLID: other.ID}}))
+
+	return NewExportHandler(testHost, url.NewExportURLsUseCase(repo, clicks), MockLogger{})
+}
+
+func exportRequest(t *testing.T, h *ExportHandler, owner uuid.UUID, format string) *httptest.ResponseRecorder {
+	tm := auth.NewTokenManager("secret")
+	router := webmw.AuthMiddleware(tm)(h.UserRoutes())
+
+	req := authorizedRequest(t, tm, owner, http.MethodGet, "/?format="+format, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestExport_NDJSONAcrossPages(t *testing.T) {
+	owner := uuid.New()
+	h := setupExport(t, owner, 2500)
+
+	w := exportRequest(t, h, owner, "ndjson")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	seen := make(map[string]struct{})
+	sc := bufio.NewScanner(w.Body)
+	for sc.Scan() {
+		var item dto.ExportedURLResponse
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &item))
+		seen[item.Hash] = struct{}{}
+	}
+	assert.Len(t, seen, 2500)
+	assert.NotContains(t, seen, "other")
+}
+
+func TestExport_JSON(t *testing.T) {
+	owner := uuid.New()
+	h := setupExport(t, owner, 3)
+
+	w := exportRequest(t, h, owner, "json")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var items []dto.ExportedURLResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&items))
+	require.Len(t, items, 3)
+	assert.Equal(t, testHost+items[0].Hash, items[0].ShortURL)
+}
+
+func TestExport_CSV(t *testing.T) {
+	owner := uuid.New()
+	h := setupExport(t, owner, 2)
+
+	w := exportRequest(t, h, owner, "csv")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "urls.csv")
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "hash", records[0][0])
+}
+
+func TestExport_EmptyJSON(t *testing.T) {
+	h := setupExport(t, uuid.New(), 0)
+
+	w := exportRequest(t, h, uuid.New(), "json")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "[]", strings.TrimSpace(w.Body.String()))
+}
+
+func TestExport_UnknownFormat(t *testing.T) {
+	owner := uuid.New()
+	h := setupExport(t, owner, 1)
+
+	w := exportRequest(t, h, owner, "xml")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestExport_Admin(t *testing.T) {
+	h := setupExport(t, uuid.New(), 2)
+	router := h.AdminRoutes("10.0.0.0/8")
+
+	req := httptest.NewRequest(http.MethodGet, "/?format=ndjson", nil)
+	req.Header.Set("X-Real-IP", "10.1.2.3")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var clicksByHash = map[string]int{}
+	sc := bufio.NewScanner(w.Body)
+	for sc.Scan() {
+		var item dto.ExportedURLResponse
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &item))
+		clicksByHash[item.Hash] = item.Clicks
+	}
+	assert.Len(t, clicksByHash, 3)
+	assert.Equal(t, 2, clicksByHash["other"])
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Real-IP", "192.168.0.1")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/helpers"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// ImportHandler принимает поток ссылок в NDJSON или CSV, сохраняет их
+// порциями и построчно отдаёт итог в NDJSON, не держа весь поток в памяти.
+type ImportHandler struct {
+	baseURL   string
+	usecase   url.BatchCreateURLUseCase
+	maxBody   int64
+	chunkSize int
+	logger    shared.Logger
+}
+
+func NewImportHandler(host string, uc url.BatchCreateURLUseCase, maxBody int64, chunkSize int, l shared.Logger) *ImportHandler {
+	return &ImportHandler{host, uc, maxBody, chunkSize, l}
+}
+
+func (h *ImportHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer)
+	r.Post("/", h.post)
+	return r
+}
+
+// importEntry — прочитанная запись, ожидающая сохранения. Err заполнен,
+// если запись не удалось разобрать.
+type importEntry struct {
+	line int
+	cmd  command.CreateURLEntryCommand
+	err  error
+}
+
+func (h *ImportHandler) post(w http.ResponseWriter, r *http.Request) {
+	// Ограничение действует после распаковки gzip, поэтому защищает и от
+	// чрезмерно сжатых данных.
+	body := http.MaxBytesReader(w, r.Body, h.maxBody)
+
+	src, err := newImportReader(r.Header.Get("Content-Type"), body)
+	if errors.Is(err, errUnsupportedImportFormat) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		_ = json.NewEncoder(w).Encode(helpers.ErrorResponse{
+			ID:      "unsupported_media_type",
+			Message: "Поддерживаются application/x-ndjson и text/csv",
+			TraceID: w.Header().Get(helpers.TraceIDHeader),
+		})
+		return
+	}
+	if err != nil {
+		helpers.HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	imp := &importRun{
+		h:      h,
+		enc:    json.NewEncoder(w),
+		flush:  http.NewResponseController(w).Flush,
+		userID: userIDFromRequest(r),
+		chunk:  make([]importEntry, 0, h.chunkSize),
+	}
+
+	for {
+		rec, line, readErr := src.Next()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+
+		var invalid errs.ValidationError
+		if readErr != nil && !errors.As(readErr, &invalid) {
+			imp.fail(r.Context(), readErr)
+			return
+		}
+
+		imp.chunk = append(imp.chunk, importEntry{line: line, cmd: h.toCommand(rec), err: readErr})
+		if len(imp.chunk) == h.chunkSize {
+			if err := imp.save(r.Context()); err != nil {
+				imp.fail(r.Context(), err)
+				return
+			}
+		}
+	}
+
+	if err := imp.save(r.Context()); err != nil {
+		imp.fail(r.Context(), err)
+		return
+	}
+
+	_ = imp.enc.Encode(dto.ImportStatusLine{Progress: imp.progress, Done: true})
+	_ = imp.flush()
+	h.logger.InfoContext(r.Context(), "import finished",
+		"processed", imp.progress.Processed,
+		"created", imp.progress.Created,
+		"existing", imp.progress.Existing,
+		"invalid", imp.progress.Invalid,
+	)
+}
+
+func (h *ImportHandler) toCommand(rec dto.BatchShortenURLRequest) command.CreateURLEntryCommand {
+	cmd := command.CreateURLEntryCommand{
+		OriginalURL:  rec.URL,
+		Alias:        rec.Alias,
+		ExpiresAt:    rec.ExpiresAt,
+		MaxClicks:    rec.MaxClicks,
+		RedirectCode: rec.RedirectCode,
+		NoIndex:      rec.NoIndex,
+	}
+	if rec.CorrelationID != "" {
+		correlationID := rec.CorrelationID
+		cmd.CorrelationID = &correlationID
+	}
+	return cmd
+}
+
+// importRun хранит состояние одного запроса импорта.
+type importRun struct {
+	h        *ImportHandler
+	enc      *json.Encoder
+	flush    func() error
+	userID   *uuid.UUID
+	chunk    []importEntry
+	progress dto.ImportProgress
+}
+
+// save сохраняет накопленную порцию, пишет итог по каждой записи и строку
+// прогресса.
+func (imp *importRun) save(ctx context.Context) error {
+	if len(imp.chunk) == 0 {
+		return nil
+	}
+
+	cmd := command.CreateBatchURLEntryCommand{UserID: imp.userID}
+	for _, e := range imp.chunk {
+		if e.err == nil {
+			cmd.Entries = append(cmd.Entries, e.cmd)
+		}
+	}
+
+	var results []model.BatchResult
+	if len(cmd.Entries) > 0 {
+		ctx, cancel := context.WithTimeout(ctx, writeRequestTimeout)
+		var err error
+		results, err = imp.h.usecase.Run(ctx, cmd)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, e := range imp.chunk {
+		res := model.BatchResult{CorrelationID: e.cmd.CorrelationID, Status: model.BatchInvalid, Err: e.err}
+		if e.err == nil {
+			res, results = results[0], results[1:]
+		}
+		_ = imp.enc.Encode(imp.resultLine(e.line, res))
+	}
+
+	imp.chunk = imp.chunk[:0]
+	_ = imp.enc.Encode(dto.ImportStatusLine{Progress: imp.progress})
+	return imp.flush()
+}
+
+func (imp *importRun) resultLine(line int, res model.BatchResult) dto.ImportResultLine {
+	out := dto.ImportResultLine{Line: line, Status: string(res.Status)}
+	if res.CorrelationID != nil {
+		out.CorrelationID = *res.CorrelationID
+	}
+
+	imp.progress.Processed++
+	switch res.Status {
+	case model.BatchCreated:
+		imp.progress.Created++
+		out.ShortURL = imp.h.formatFullURL(res.URL.Hash)
+	case model.BatchExisting:
+		imp.progress.Existing++
+		out.ShortURL = imp.h.formatFullURL(res.URL.Hash)
+	default:
+		imp.progress.Invalid++
+		out.Error = &dto.BatchItemError{ID: errs.ID(res.Err), Message: res.Err.Error()}
+	}
+	return out
+}
+
+// fail завершает ответ строкой с ошибкой: заголовки уже отправлены, и
+// сменить код ответа нельзя.
+func (imp *importRun) fail(ctx context.Context, err error) {
+	itemErr := &dto.BatchItemError{ID: errs.ID(err), Message: err.Error()}
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		itemErr = &dto.BatchItemError{ID: "payload_too_large", Message: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		itemErr = &dto.BatchItemError{ID: "timeout", Message: "Не удалось сохранить записи вовремя"}
+	}
+
+	_ = imp.enc.Encode(dto.ImportStatusLine{Progress: imp.progress, Error: itemErr})
+	_ = imp.flush()
+	imp.h.logger.ErrorContext(ctx, "import aborted", "error", err, "processed", imp.progress.Processed)
+}
+
+func (h *ImportHandler) formatFullURL(hash string) string {
+	return h.baseURL + hash
+}
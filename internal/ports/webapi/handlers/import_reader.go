@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
+)
+
+// importReader читает записи импорта по одной. Ошибка типа
+// errs.ValidationError относится только к текущей записи, остальные
+// ошибки прерывают импорт.
+type importReader interface {
+	Next() (rec dto.BatchShortenURLRequest, line int, err error)
+}
+
+var errUnsupportedImportFormat = errors.New("unsupported import format")
+
+func newImportReader(contentType string, body io.Reader) (importReader, error) {
+	mediaType := "application/x-ndjson"
+	if contentType != "" {
+		var err error
+		if mediaType, _, err = mime.ParseMediaType(contentType); err != nil {
+			return nil, errUnsupportedImportFormat
+		}
+	}
+
+	switch mediaType {
+	case "application/x-ndjson", "application/jsonl", "application/json":
+		return &ndjsonReader{r: bufio.NewReader(body)}, nil
+	case "text/csv":
+		return newCSVReader(body)
+	default:
+		return nil, errUnsupportedImportFormat
+	}
+}
+
+// ndjsonReader читает по одному JSON-объекту на строку, пустые строки
+// пропускаются.
+type ndjsonReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+func (n *ndjsonReader) Next() (dto.BatchShortenURLRequest, int, error) {
+	for {
+		b, err := n.r.ReadBytes('\n')
+		// Последняя строка без перевода строки допустима только в конце
+		// потока, а не при обрыве чтения.
+		if err != nil && (len(b) == 0 || !errors.Is(err, io.EOF)) {
+			return dto.BatchShortenURLRequest{}, n.line, err
+		}
+		n.line++
+
+		b = bytes.TrimSpace(b)
+		if len(b) == 0 {
+			continue
+		}
+
+		var rec dto.BatchShortenURLRequest
+		if jsonErr := json.Unmarshal(b, &rec); jsonErr != nil {
+			return rec, n.line, errs.ValidationError(jsonErr.Error())
+		}
+		return rec, n.line, nil
+	}
+}
+
+// csvReader читает CSV с заголовком. Обязательна колонка original_url,
+// остальные — correlation_id, alias, expires_at (RFC 3339), max_clicks,
+// redirect_code и noindex.
+type csvReader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(body io.Reader) (*csvReader, error) {
+	r := csv.NewReader(body)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, errs.ValidationError(fmt.Sprintf("failed to read csv header: %v", err))
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["original_url"]; !ok {
+		return nil, errs.ValidationError("csv header must contain original_url")
+	}
+
+	return &csvReader{r: r, columns: columns}, nil
+}
+
+func (c *csvReader) Next() (dto.BatchShortenURLRequest, int, error) {
+	record, err := c.r.Read()
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return dto.BatchShortenURLRequest{}, parseErr.Line, errs.ValidationError(parseErr.Error())
+	}
+	if err != nil {
+		return dto.BatchShortenURLRequest{}, 0, err
+	}
+	line, _ := c.r.FieldPos(0)
+
+	rec := dto.BatchShortenURLRequest{
+		URL:           c.field(record, "original_url"),
+		CorrelationID: c.field(record, "correlation_id"),
+		Alias:         c.field(record, "alias"),
+	}
+
+	if v := c.field(record, "expires_at"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return rec, line, errs.ValidationError("expires_at must be in RFC 3339 format")
+		}
+		rec.ExpiresAt = &t
+	}
+
+	if v := c.field(record, "max_clicks"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return rec, line, errs.ValidationError("max_clicks must be an integer")
+		}
+		rec.MaxClicks = &n
+	}
+
+	if v := c.field(record, "redirect_code"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return rec, line, errs.ValidationError("redirect_code must be an integer")
+		}
+		rec.RedirectCode = n
+	}
+
+	if v := c.field(record, "noindex"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return rec, line, errs.ValidationError("noindex must be true or false")
+		}
+		rec.NoIndex = b
+	}
+
+	return rec, line, nil
+}
+
+func (c *csvReader) field(record []string, name string) string {
+	i, ok := c.columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/hashgen"
+	infr "github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupImport собирает обработчик импорта над собственным хранилищем, не
+// трогая общий repo остальных тестов.
+func setupImport(maxBody int64, chunkSize int) (*ImportHandler, repository.URLRepository) {
+	r := infr.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	uc := url.NewBatchCreateURLUseCase(r, shared.SystemClock{}, testAliasPolicy, hashgen.NewRandomGenerator(8))
+	return NewImportHandler(testHost, uc, maxBody, chunkSize, MockLogger{}), r
+}
+
+// readImport разбирает ответ импорта на итоги по записям и строки прогресса.
+func readImport(t *testing.T, body string) ([]dto.ImportResultLine, []dto.ImportStatusLine) {
+	var (
+		results  []dto.ImportResultLine
+		statuses []dto.ImportStatusLine
+	)
+
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		if strings.HasPrefix(sc.Text(), `{"progress"`) {
+			var s dto.ImportStatusLine
+			require.NoError(t, json.Unmarshal(sc.Bytes(), &s))
+			statuses = append(statuses, s)
+			continue
+		}
+		var r dto.ImportResultLine
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &r))
+		results = append(results, r)
+	}
+	return results, statuses
+}
+
+func TestImport_NDJSON(t *testing.T) {
+	h, _ := setupImport(1<<20, 2)
+
+	body := `{"correlation_id":"1","original_url":"https://a.example"}
+{"correlation_id":"2","original_url":"https://b.example"}
+
+not json
+{"correlation_id":"4","original_url":"https://a.example"}
+`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	h.Routes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+	results, statuses := readImport(t, w.Body.String())
+	require.Len(t, results, 4)
+	assert.Equal(t, []int{1, 2, 4, 5}, []int{results[0].Line, results[1].Line, results[2].Line, results[3].Line})
+	assert.Equal(t, "created", results[0].Status)
+	assert.Equal(t, "created", results[1].Status)
+	assert.Equal(t, "invalid", results[2].Status)
+	assert.Equal(t, "validation_error", results[2].Error.ID)
+	assert.Equal(t, "existing", results[3].Status)
+	assert.Equal(t, results[0].ShortURL, results[3].ShortURL)
+
+	require.Len(t, statuses, 3, "две порции и итог")
+	last := statuses[len(statuses)-1]
+	assert.True(t, last.Done)
+	assert.Equal(t, dto.ImportProgress{Processed: 4, Created: 2, Existing: 1, Invalid: 1}, last.Progress)
+}
+
+func TestImport_CSV(t *testing.T) {
+	h, r := setupImport(1<<20, 100)
+
+	body := "original_url,correlation_id,alias,max_clicks\n" +
+		"https://a.example,1,promo,\n" +
+		"https://b.example,2,,ten\n" +
+		"https://c.example,3,,5\n"
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	w := httptest.NewRecorder()
+	h.Routes().ServeHTTP(w, req)
+
+	results, statuses := readImport(t, w.Body.String())
+	require.Len(t, results, 3)
+	assert.Equal(t, testHost+"promo", results[0].ShortURL)
+	assert.Equal(t, 2, results[0].Line)
+	assert.Equal(t, "invalid", results[1].Status)
+	assert.Equal(t, "created", results[2].Status)
+	assert.True(t, statuses[len(statuses)-1].Done)
+
+	m, err := r.FindByHash(context.Background(), "promo")
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.example", m.OriginalURL)
+}
+
+func TestImport_CSVWithoutURLColumn(t *testing.T) {
+	h, _ := setupImport(1<<20, 100)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("url\nhttps://a.example\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+	h.Routes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestImport_BodyTooLarge(t *testing.T) {
+	h, _ := setupImport(80, 1)
+
+	body := strings.Repeat(`{"original_url":"https://a.example/very/long/path"}`+"\n", 3)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	h.Routes().ServeHTTP(w, req)
+
+	results, statuses := readImport(t, w.Body.String())
+	assert.Len(t, results, 1)
+	last := statuses[len(statuses)-1]
+	assert.False(t, last.Done)
+	require.NotNil(t, last.Error)
+	assert.Equal(t, "payload_too_large", last.Error.ID)
+}
+
+func TestImport_UnsupportedFormat(t *testing.T) {
+	h, _ := setupImport(1<<20, 100)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("<xml/>"))
+	req.Header.Set("Content-Type", "application/xml")
+	w := httptest.NewRecorder()
+	h.Routes().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
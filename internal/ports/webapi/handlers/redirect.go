@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+)
+
+// RedirectPolicy — настройки перенаправлений для ссылок, у которых они не
+// заданы. Нулевой Code означает 307.
+type RedirectPolicy struct {
+	Code int
+	// MaxAge — сколько клиенты могут помнить постоянное перенаправление.
+	MaxAge  time.Duration
+	NoIndex bool
+}
+
+// redirect отвечает перенаправлением на m.OriginalURL. Кэшировать
+// разрешается только постоянные перенаправления и не дольше срока жизни
+// ссылки; временные и ссылки с лимитом переходов не кэшируются, чтобы
+// каждый переход дошёл до сервиса.
+func (p RedirectPolicy) redirect(w http.ResponseWriter, m *model.URL, now time.Time) {
+	code := m.RedirectCode
+	if code == 0 {
+		code = p.Code
+	}
+	if code == 0 {
+		code = http.StatusTemporaryRedirect
+	}
+
+	header := w.Header()
+	header.Set("Location", m.OriginalURL)
+	if m.NoIndex || p.NoIndex {
+		header.Set("X-Robots-Tag", "noindex")
+	}
+
+	if maxAge := p.maxAge(m, code, now); maxAge > 0 {
+		header.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge/time.Second)))
+		header.Set("Expires", now.Add(maxAge).UTC().Format(http.TimeFormat))
+	} else {
+		header.Set("Cache-Control", "private, no-cache")
+		header.Set("Expires", time.Unix(0, 0).UTC().Format(http.TimeFormat))
+	}
+
+	w.WriteHeader(code)
+}
+
+func (p RedirectPolicy) maxAge(m *model.URL, code int, now time.Time) time.Duration {
+	if !model.IsPermanentRedirect(code) || m.MaxClicks != nil {
+		return 0
+	}
+
+	maxAge := p.MaxAge
+	if m.ExpiresAt != nil {
+		maxAge = min(maxAge, m.ExpiresAt.Sub(now))
+	}
+	return maxAge.Truncate(time.Second)
+}
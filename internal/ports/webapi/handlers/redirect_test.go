@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createRedirectURL(t *testing.T, hash string, configure func(m *model.URL)) *model.URL {
+	m, err := model.NewURL("https://example.com/"+hash, hash, nil, nil, time.Now())
+	require.NoError(t, err)
+	configure(m)
+	require.NoError(t, repo.Create(context.Background(), m))
+	return m
+}
+
+func TestRedirect_CodesAndCacheHeaders(t *testing.T) {
+	h := setupTest()
+	h.redirects = RedirectPolicy{Code: http.StatusFound, MaxAge: time.Hour}
+	router := h.Routes()
+
+	soon := time.Now().Add(10 * time.Minute)
+	limit := 5
+	createRedirectURL(t, "default", func(m *model.URL) {})
+	createRedirectURL(t, "permanent", func(m *model.URL) { m.RedirectCode = http.StatusMovedPermanently })
+	createRedirectURL(t, "expiring", func(m *model.URL) {
+		m.RedirectCode = http.StatusPermanentRedirect
+		m.ExpiresAt = &soon
+	})
+	createRedirectURL(t, "limited", func(m *model.URL) {
+		m.RedirectCode = http.StatusPermanentRedirect
+		m.MaxClicks = &limit
+	})
+
+	tests := []struct {
+		hash         string
+		code         int
+		cacheControl string // префикс
+	}{
+		{"default", http.StatusFound, "private, no-cache"},
+		{"permanent", http.StatusMovedPermanently, "public, max-age=3600"},
+		// Срок ссылки ограничивает кэш; пока идёт тест, проходит доля секунды.
+		{"expiring", http.StatusPermanentRedirect, "public, max-age=59"},
+		{"limited", http.StatusPermanentRedirect, "private, no-cache"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hash, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+tt.hash, nil))
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, "https://example.com/"+tt.hash, w.Header().Get("Location"))
+			assert.True(t, strings.HasPrefix(w.Header().Get("Cache-Control"), tt.cacheControl),
+				"Cache-Control: %s", w.Header().Get("Cache-Control"))
+			_, err := http.ParseTime(w.Header().Get("Expires"))
+			assert.NoError(t, err)
+			assert.Empty(t, w.Header().Get("X-Robots-Tag"))
+		})
+	}
+}
+
+func TestRedirect_NoIndex(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+
+	createRedirectURL(t, "hidden", func(m *model.URL) { m.NoIndex = true })
+	createRedirectURL(t, "visible", func(m *model.URL) {})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/hidden", nil))
+	assert.Equal(t, "noindex", w.Header().Get("X-Robots-Tag"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/visible", nil))
+	assert.Empty(t, w.Header().Get("X-Robots-Tag"))
+
+	h.redirects.NoIndex = true
+	w = httptest.NewRecorder()
+	h.Routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/visible", nil))
+	assert.Equal(t, "noindex", w.Header().Get("X-Robots-Tag"))
+}
+
+func TestRedirect_HeadDoesNotCountClick(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+
+	limit := 1
+	m := createRedirectURL(t, "once", func(m *model.URL) { m.MaxClicks = &limit })
+
+	for range 3 {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/once", nil))
+		assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+		assert.Equal(t, m.OriginalURL, w.Header().Get("Location"))
+		assert.Zero(t, w.Body.Len())
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/once", nil))
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code, "HEAD не расходует лимит")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/once", nil))
+	assert.Equal(t, http.StatusGone, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	require.NoError(t, recorder.Stop(context.Background()))
+	counts, err := clicks.CountByURL(context.Background(), []uuid.UUID{m.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, counts[m.ID])
+}
+
+func TestShorten_RedirectOptions(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/shorten",
+		strings.NewReader(`{"url":"https://example.com/moved","alias":"moved","redirect_code":308,"noindex":true}`)))
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	m, err := repo.FindByHash(context.Background(), "moved")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusPermanentRedirect, m.RedirectCode)
+	assert.True(t, m.NoIndex)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/shorten",
+		strings.NewReader(`{"url":"https://example.com/bad","redirect_code":200}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
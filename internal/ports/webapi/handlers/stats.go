@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/helpers"
+	webmw "github.com/amberdance/url-shortener/internal/ports/webapi/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+type StatsHandler struct {
+	trustedSubnet string
+	usecase       url.GetStatsUseCase
+	logger        shared.Logger
+}
+
+func NewStatsHandler(trustedSubnet string, uc url.GetStatsUseCase, l shared.Logger) *StatsHandler {
+	return &StatsHandler{trustedSubnet, uc, l}
+}
+
+func (h *StatsHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(webmw.TrustedSubnetMiddleware(h.trustedSubnet))
+	r.Get("/", h.get)
+	return r
+}
+
+func (h *StatsHandler) get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readRequestTimeout)
+	defer cancel()
+
+	stats, err := h.usecase.Run(ctx)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), err.Error())
+		helpers.HandleError(w, errs.InternalError("Не удалось получить статистику"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(dto.StatsResponse{URLs: stats.URLs, Users: stats.Users})
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats_Success(t *testing.T) {
+	h := setupTest()
+	userID := uuid.New()
+
+	for _, original := range []string{"https://hard2code.ru", "https://google.com"} {
+		_, err := h.usecases.Create.Run(context.Background(), command.CreateURLEntryCommand{
+			OriginalURL: original,
+			UserID:      &userID,
+		})
+		assert.NoError(t, err)
+	}
+
+	router := NewStatsHandler("10.0.0.0/8", url.NewGetStatsUseCase(repo), MockLogger{}).Routes()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Real-IP", "10.1.2.3")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var resp dto.StatsResponse
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&resp))
+	assert.Equal(t, dto.StatsResponse{URLs: 2, Users: 1}, resp)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/helpers"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (h *URLShortenerHandler) updateURL(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		helpers.HandleError(w, errs.UnauthorizedError("Пользователь не авторизован"))
+		return
+	}
+
+	var req dto.UpdateURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helpers.HandleError(w, errs.ValidationError(err.Error()))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), writeRequestTimeout)
+	defer cancel()
+
+	m, err := h.usecases.Update.Run(ctx, command.UpdateURLCommand{
+		UserID: userID,
+		Hash:   chi.URLParam(r, "hash"),
+		Change: model.URLChange{
+			OriginalURL:    req.OriginalURL,
+			ExpiresAt:      req.ExpiresAt.Value,
+			ClearExpiresAt: req.ExpiresAt.Set && req.ExpiresAt.Value == nil,
+			MaxClicks:      req.MaxClicks.Value,
+			ClearMaxClicks: req.MaxClicks.Set && req.MaxClicks.Value == nil,
+			RedirectCode:   req.RedirectCode,
+			NoIndex:        req.NoIndex,
+		},
+	})
+	if err != nil {
+		h.handleEditError(w, r, err, "Не удалось изменить ссылку")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(h.toURLResponse(m))
+}
+
+func (h *URLShortenerHandler) urlRevisions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		helpers.HandleError(w, errs.UnauthorizedError("Пользователь не авторизован"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readRequestTimeout)
+	defer cancel()
+
+	revs, err := h.usecases.Revisions.Run(ctx, command.GetURLRevisionsCommand{
+		UserID: userID,
+		Hash:   chi.URLParam(r, "hash"),
+	})
+	if err != nil {
+		h.handleEditError(w, r, err, "Не удалось получить историю ссылки")
+		return
+	}
+
+	res := make([]dto.URLRevisionResponse, 0, len(revs))
+	for _, rev := range revs {
+		res = append(res, dto.URLRevisionResponse{
+			ID:           rev.ID.String(),
+			OriginalURL:  rev.OriginalURL,
+			ExpiresAt:    rev.ExpiresAt,
+			MaxClicks:    rev.MaxClicks,
+			RedirectCode: rev.RedirectCode,
+			NoIndex:      rev.NoIndex,
+			ReplacedAt:   rev.CreatedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (h *URLShortenerHandler) rollbackURL(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		helpers.HandleError(w, errs.UnauthorizedError("Пользователь не авторизован"))
+		return
+	}
+
+	revisionID, err := uuid.Parse(chi.URLParam(r, "revision"))
+	if err != nil {
+		helpers.HandleError(w, errs.NotFoundError("Не найден ресурс"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), writeRequestTimeout)
+	defer cancel()
+
+	m, err := h.usecases.Rollback.Run(ctx, command.RollbackURLCommand{
+		UserID:     userID,
+		Hash:       chi.URLParam(r, "hash"),
+		RevisionID: revisionID,
+	})
+	if err != nil {
+		h.handleEditError(w, r, err, "Не удалось откатить ссылку")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(h.toURLResponse(m))
+}
+
+// handleEditError отвечает на ошибку изменения ссылки; непредвиденные
+// ошибки логируются и заменяются сообщением message.
+func (h *URLShortenerHandler) handleEditError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		w.WriteHeader(http.StatusGatewayTimeout)
+		return
+	}
+
+	var (
+		notFoundErr   errs.NotFoundError
+		validationErr errs.ValidationError
+		dupErr        errs.DuplicateEntryError
+	)
+	switch {
+	case errors.As(err, &notFoundErr):
+		helpers.HandleError(w, errs.NotFoundError("Не найден ресурс"))
+	case errors.As(err, &validationErr):
+		helpers.HandleError(w, validationErr)
+	case errors.As(err, &dupErr):
+		helpers.HandleError(w, errs.DuplicateEntryError("Адрес уже сокращён другой ссылкой"))
+	default:
+		h.logger.ErrorContext(r.Context(), err.Error())
+		helpers.HandleError(w, errs.InternalError(message))
+	}
+}
+
+func (h *URLShortenerHandler) toURLResponse(m *model.URL) dto.URLResponse {
+	return dto.URLResponse{
+		Hash:         m.Hash,
+		ShortURL:     h.formatFullURL(m.Hash),
+		OriginalURL:  m.OriginalURL,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+		ExpiresAt:    m.ExpiresAt,
+		MaxClicks:    m.MaxClicks,
+		RedirectCode: m.RedirectCode,
+		NoIndex:      m.NoIndex,
+	}
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
+	webmw "github.com/amberdance/url-shortener/internal/ports/webapi/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateURL_ChangesDestinationAndKeepsHistory(t *testing.T) {
+	h := setupTest()
+	tm := auth.NewTokenManager("secret")
+	router := webmw.AuthMiddleware(tm)(h.Routes())
+	userID := uuid.New()
+
+	m, err := model.NewURL("https://old.example", "promo", nil, &userID, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), m))
+
+	req := authorizedRequest(t, tm, userID, http.MethodPatch, "/api/urls/promo",
+		strings.NewReader(`{"original_url":"https://new.example","max_clicks":10}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var updated dto.URLResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&updated))
+	assert.Equal(t, "https://new.example", updated.OriginalURL)
+	assert.Equal(t, testHost+"promo", updated.ShortURL)
+	require.NotNil(t, updated.UpdatedAt)
+	require.NotNil(t, updated.MaxClicks)
+	assert.Equal(t, 10, *updated.MaxClicks)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/promo", nil))
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://new.example", w.Header().Get("Location"))
+
+	// null снимает лимит переходов.
+	req = authorizedRequest(t, tm, userID, http.MethodPatch, "/api/urls/promo", strings.NewReader(`{"max_clicks":null}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var cleared dto.URLResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&cleared))
+	assert.Nil(t, cleared.MaxClicks)
+
+	req = authorizedRequest(t, tm, userID, http.MethodGet, "/api/urls/promo/revisions", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var revs []dto.URLRevisionResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&revs))
+	require.Len(t, revs, 2)
+	assert.Equal(t, "https://new.example", revs[0].OriginalURL)
+	assert.Equal(t, "https://old.example", revs[1].OriginalURL)
+	assert.Nil(t, revs[1].MaxClicks)
+
+	req = authorizedRequest(t, tm, userID, http.MethodPost, "/api/urls/promo/revisions/"+revs[1].ID+"/rollback", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&updated))
+	assert.Equal(t, "https://old.example", updated.OriginalURL)
+
+	revisions, err := repo.FindRevisions(context.Background(), m.ID)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 3, "откат тоже сохраняет ревизию")
+}
+
+func TestUpdateURL_ChangesRedirectOptions(t *testing.T) {
+	h := setupTest()
+	tm := auth.NewTokenManager("secret")
+	router := webmw.AuthMiddleware(tm)(h.Routes())
+	userID := uuid.New()
+
+	m, err := model.NewURL("https://old.example", "moved", nil, &userID, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), m))
+
+	req := authorizedRequest(t, tm, userID, http.MethodPatch, "/api/urls/moved",
+		strings.NewReader(`{"redirect_code":301,"noindex":true}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var updated dto.URLResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&updated))
+	assert.Equal(t, 301, updated.RedirectCode)
+	assert.True(t, updated.NoIndex)
+	assert.Equal(t, "https://old.example", updated.OriginalURL)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/moved", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "noindex", w.Header().Get("X-Robots-Tag"))
+
+	req = authorizedRequest(t, tm, userID, http.MethodGet, "/api/urls/moved/revisions", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var revs []dto.URLRevisionResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&revs))
+	require.Len(t, revs, 1)
+
+	req = authorizedRequest(t, tm, userID, http.MethodPost, "/api/urls/moved/revisions/"+revs[0].ID+"/rollback", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var restored dto.URLResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&restored))
+	assert.Zero(t, restored.RedirectCode)
+	assert.False(t, restored.NoIndex)
+}
+
+func TestUpdateURL_Errors(t *testing.T) {
+	h := setupTest()
+	tm := auth.NewTokenManager("secret")
+	router := webmw.AuthMiddleware(tm)(h.Routes())
+	userID := uuid.New()
+
+	for hash, original := range map[string]string{"first": "https://a.example", "second": "https://b.example"} {
+		m, err := model.NewURL(original, hash, nil, &userID, time.Now())
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(context.Background(), m))
+	}
+
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		target string
+		body   string
+		want   int
+	}{
+		{"address taken by another link", userID, "/api/urls/first", `{"original_url":"https://b.example"}`, http.StatusConflict},
+		{"empty change", userID, "/api/urls/first", `{}`, http.StatusBadRequest},
+		{"empty address", userID, "/api/urls/first", `{"original_url":" "}`, http.StatusBadRequest},
+		{"past expiration", userID, "/api/urls/first", `{"expires_at":"2000-01-01T00:00:00Z"}`, http.StatusBadRequest},
+		{"invalid redirect code", userID, "/api/urls/first", `{"redirect_code":303}`, http.StatusBadRequest},
+		{"malformed body", userID, "/api/urls/first", `{`, http.StatusBadRequest},
+		{"foreign link", uuid.New(), "/api/urls/first", `{"original_url":"https://c.example"}`, http.StatusNotFound},
+		{"unknown link", userID, "/api/urls/missing", `{"original_url":"https://c.example"}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := authorizedRequest(t, tm, tt.userID, http.MethodPatch, tt.target, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+
+	req := authorizedRequest(t, tm, userID, http.MethodPost, "/api/urls/first/revisions/"+uuid.NewString()+"/rollback", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
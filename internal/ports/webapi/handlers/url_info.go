@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/amberdance/url-shortener/internal/app/command"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/helpers"
+	"github.com/go-chi/chi/v5"
+)
+
+// urlInfo и findURL отдают владельцу полную запись его ссылки; чужие
+// ссылки не отличаются от несуществующих.
+func (h *URLShortenerHandler) urlInfo(w http.ResponseWriter, r *http.Request) {
+	info, ok := h.findURLInfo(w, r, command.GetURLInfoCommand{
+		Hash:   chi.URLParam(r, "hash"),
+		UserID: userIDFromRequest(r),
+	})
+	if ok {
+		h.writeJSON(w, h.toURLInfoResponse(info))
+	}
+}
+
+// findURL — обратный поиск ссылки владельца по исходному адресу.
+func (h *URLShortenerHandler) findURL(w http.ResponseWriter, r *http.Request) {
+	original := strings.TrimSpace(r.URL.Query().Get("original_url"))
+	if original == "" {
+		helpers.HandleError(w, errs.ValidationError("Не передан параметр original_url"))
+		return
+	}
+
+	info, ok := h.findURLInfo(w, r, command.GetURLInfoCommand{
+		OriginalURL: original,
+		UserID:      userIDFromRequest(r),
+	})
+	if ok {
+		h.writeJSON(w, h.toURLInfoResponse(info))
+	}
+}
+
+// publicURLInfo отвечает на GET /{hash} с Accept: application/json. Ответ
+// доступен всем, поэтому в нём нет владельца и correlation_id, а удалённые
+// ссылки не находятся.
+func (h *URLShortenerHandler) publicURLInfo(w http.ResponseWriter, r *http.Request, hash string) {
+	info, ok := h.findURLInfo(w, r, command.GetURLInfoCommand{Hash: hash})
+	if !ok {
+		return
+	}
+	if info.Status == model.URLDeleted {
+		helpers.HandleError(w, errs.NotFoundError("Не найден ресурс"))
+		return
+	}
+
+	res := h.toURLInfoResponse(info)
+	res.UserID = nil
+	res.CorrelationID = nil
+	h.writeJSON(w, res)
+}
+
+func (h *URLShortenerHandler) findURLInfo(w http.ResponseWriter, r *http.Request, cmd command.GetURLInfoCommand) (*model.URLInfo, bool) {
+	ctx, cancel := context.WithTimeout(r.Context(), readRequestTimeout)
+	defer cancel()
+
+	info, err := h.usecases.GetInfo.Run(ctx, cmd)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return nil, false
+		}
+
+		var notFoundErr errs.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			helpers.HandleError(w, errs.NotFoundError("Не найден ресурс"))
+			return nil, false
+		}
+
+		h.logger.ErrorContext(r.Context(), err.Error())
+		helpers.HandleError(w, errs.InternalError("Не удалось получить ссылку"))
+		return nil, false
+	}
+	return info, true
+}
+
+func (h *URLShortenerHandler) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (h *URLShortenerHandler) toURLInfoResponse(info *model.URLInfo) dto.URLInfoResponse {
+	res := dto.URLInfoResponse{
+		Hash:          info.Hash,
+		ShortURL:      h.formatFullURL(info.Hash),
+		OriginalURL:   info.OriginalURL,
+		CorrelationID: info.CorrelationID,
+		CreatedAt:     info.CreatedAt,
+		UpdatedAt:     info.UpdatedAt,
+		DeletedAt:     info.DeletedAt,
+		ExpiresAt:     info.ExpiresAt,
+		MaxClicks:     info.MaxClicks,
+		RedirectCode:  info.RedirectCode,
+		NoIndex:       info.NoIndex,
+		Clicks:        info.TotalClicks,
+		Status:        string(info.Status),
+	}
+	if info.UserID != nil {
+		owner := info.UserID.String()
+		res.UserID = &owner
+	}
+	return res
+}
+
+// wantsJSON сообщает, что клиент предпочитает JSON странице, на которую
+// ведёт ссылка: application/json указан в Accept с весом не ниже, чем
+// text/html. Браузеры и curl по умолчанию JSON не запрашивают.
+func wantsJSON(r *http.Request) bool {
+	jsonQ, htmlQ := 0.0, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		switch mediaType {
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		case "text/html":
+			htmlQ = max(htmlQ, q)
+		}
+	}
+	return jsonQ > 0 && jsonQ >= htmlQ
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
+	webmw "github.com/amberdance/url-shortener/internal/ports/webapi/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLInfo(t *testing.T) {
+	h := setupTest()
+	tm := auth.NewTokenManager("secret")
+	router := webmw.AuthMiddleware(tm)(h.Routes())
+	userID := uuid.New()
+	correlationID := "c-1"
+
+	m, err := model.NewURL("https://hard2code.ru/page", "info", &correlationID, &userID, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), m))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/info", nil))
+	require.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	require.NoError(t, recorder.Stop(context.Background()))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest(t, tm, userID, http.MethodGet, "/api/urls/info", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var info dto.URLInfoResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&info))
+	assert.Equal(t, "info", info.Hash)
+	assert.Equal(t, testHost+"info", info.ShortURL)
+	assert.Equal(t, "https://hard2code.ru/page", info.OriginalURL)
+	assert.Equal(t, &correlationID, info.CorrelationID)
+	require.NotNil(t, info.UserID)
+	assert.Equal(t, userID.String(), *info.UserID)
+	assert.Equal(t, 1, info.Clicks)
+	assert.Equal(t, "active", info.Status)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest(t, tm, userID, http.MethodGet, "/api/urls?original_url="+url.QueryEscape("https://hard2code.ru/page"), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var found dto.URLInfoResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&found))
+	assert.Equal(t, "info", found.Hash)
+
+	require.NoError(t, repo.DeleteBatch(context.Background(), map[uuid.UUID][]string{userID: []string{"info"}}))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, authorizedRequest(t, tm, userID, http.MethodGet, "/api/urls/info", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var deleted dto.URLInfoResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&deleted))
+	assert.Equal(t, "deleted", deleted.Status)
+	assert.NotNil(t, deleted.DeletedAt)
+}
+
+func TestURLInfo_NotFound(t *testing.T) {
+	h := setupTest()
+	tm := auth.NewTokenManager("secret")
+	router := webmw.AuthMiddleware(tm)(h.Routes())
+	owner := uuid.New()
+
+	m, err := model.NewURL("https://hard2code.ru/foreign", "foreign", nil, &owner, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), m))
+
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{"/api/urls/missing", http.StatusNotFound},
+		{"/api/urls/foreign", http.StatusNotFound},
+		{"/api/urls?original_url=https%3A%2F%2Fnowhere.example", http.StatusNotFound},
+		{"/api/urls?original_url=" + url.QueryEscape("https://hard2code.ru/foreign"), http.StatusNotFound},
+		{"/api/urls", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, authorizedRequest(t, tm, uuid.New(), http.MethodGet, tt.target, nil))
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestURLInfo_Unauthorized(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+
+	for _, target := range []string{"/api/urls/info", "/api/urls?original_url=https%3A%2F%2Fhard2code.ru"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code, target)
+	}
+}
+
+func TestGet_AcceptJSONReturnsMetadata(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+	userID := uuid.New()
+	correlationID := "c-1"
+
+	m, err := model.NewURL("https://hard2code.ru", "meta", &correlationID, &userID, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(context.Background(), m))
+
+	req := httptest.NewRequest(http.MethodGet, "/meta", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Values("Vary"), "Accept")
+	var info dto.URLInfoResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&info))
+	assert.Equal(t, "https://hard2code.ru", info.OriginalURL)
+	assert.Nil(t, info.UserID, "публичный ответ не раскрывает владельца")
+	assert.Nil(t, info.CorrelationID)
+
+	require.NoError(t, recorder.Stop(context.Background()))
+	counts, err := clicks.CountByURL(context.Background(), []uuid.UUID{m.ID})
+	require.NoError(t, err)
+	assert.Zero(t, counts[m.ID], "запрос метаданных не считается переходом")
+
+	require.NoError(t, repo.DeleteBatch(context.Background(), map[uuid.UUID][]string{userID: []string{"meta"}}))
+	req = httptest.NewRequest(http.MethodGet, "/meta", nil)
+	req.Header.Set("Accept", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWantsJSON(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", false},
+		{"application/json", true},
+		{"application/json, text/plain, */*", true},
+		{"text/html;q=0.5, application/json", true},
+		{"text/html, application/json;q=0.9", false},
+		{"application/json;q=0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/x", nil)
+			r.Header.Set("Accept", tt.accept)
+			assert.Equal(t, tt.want, wantsJSON(r))
+		})
+	}
+}
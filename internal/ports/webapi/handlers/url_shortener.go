@@ -5,19 +5,25 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"net"
 	"net/http"
+	"net/netip"
 	"strings"
 	"time"
 
 	"github.com/amberdance/url-shortener/internal/app/command"
 	"github.com/amberdance/url-shortener/internal/app/usecase"
 	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
 	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
 	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
 	"github.com/amberdance/url-shortener/internal/ports/webapi/helpers"
+	webmw "github.com/amberdance/url-shortener/internal/ports/webapi/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 )
 
 const (
@@ -29,11 +35,15 @@ type URLShortenerHandler struct {
 	baseURL   string
 	usecases  usecase.URLUseCases
 	validator *validator.Validate
-	logger    shared.Logger
+	redirects RedirectPolicy
+	// proxies — подсеть обратных прокси, которым доверяется X-Real-IP.
+	proxies netip.Prefix
+	logger  shared.Logger
 }
 
-func NewURLShortenerHandler(host string, uc usecase.URLUseCases, v *validator.Validate, l shared.Logger) *URLShortenerHandler {
-	return &URLShortenerHandler{host, uc, v, l}
+func NewURLShortenerHandler(host string, uc usecase.URLUseCases, v *validator.Validate, p RedirectPolicy, trustedSubnet string, l shared.Logger) *URLShortenerHandler {
+	proxies, _ := netip.ParsePrefix(trustedSubnet)
+	return &URLShortenerHandler{host, uc, v, p, proxies, l}
 }
 
 func (h *URLShortenerHandler) Routes() chi.Router {
@@ -41,9 +51,18 @@ func (h *URLShortenerHandler) Routes() chi.Router {
 	r.Use(middleware.Recoverer)
 
 	r.Post("/", h.deprecatedPost)
-	r.Get("/{hash:[a-zA-Z0-9]+}", h.get)
+	r.Get("/{hash:[a-zA-Z0-9_-]+}", h.get)
+	r.Head("/{hash:[a-zA-Z0-9_-]+}", h.get)
 	r.Post("/api/shorten", h.shorten)
 	r.Post("/api/shorten/batch", h.shortenBatch)
+	r.With(webmw.RequireAuthMiddleware).Get("/api/user/urls", h.userURLs)
+	r.With(webmw.RequireAuthMiddleware).Delete("/api/user/urls", h.deleteUserURLs)
+	r.With(webmw.RequireAuthMiddleware).Get("/api/urls", h.findURL)
+	r.With(webmw.RequireAuthMiddleware).Get("/api/urls/{hash}", h.urlInfo)
+	r.With(webmw.RequireAuthMiddleware).Get("/api/urls/{hash}/stats", h.linkStats)
+	r.With(webmw.RequireAuthMiddleware).Patch("/api/urls/{hash}", h.updateURL)
+	r.With(webmw.RequireAuthMiddleware).Get("/api/urls/{hash}/revisions", h.urlRevisions)
+	r.With(webmw.RequireAuthMiddleware).Post("/api/urls/{hash}/revisions/{revision}/rollback", h.rollbackURL)
 	return r
 }
 
@@ -58,6 +77,12 @@ func (h *URLShortenerHandler) shorten(w http.ResponseWriter, r *http.Request) {
 	m, err := h.usecases.Create.Run(ctx, command.CreateURLEntryCommand{
 		OriginalURL:   req.URL,
 		CorrelationID: req.CorrelationID,
+		UserID:        userIDFromRequest(r),
+		Alias:         req.Alias,
+		ExpiresAt:     req.ExpiresAt,
+		MaxClicks:     req.MaxClicks,
+		RedirectCode:  req.RedirectCode,
+		NoIndex:       req.NoIndex,
 	})
 
 	w.Header().Set("Content-Type", "application/json")
@@ -75,6 +100,11 @@ func (h *URLShortenerHandler) shorten(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if reqErr := requestError(err); reqErr != nil {
+			helpers.HandleError(w, reqErr)
+			return
+		}
+
 		helpers.HandleError(w, errs.ValidationError("Не удалось сформировать ссылку"))
 		return
 	}
@@ -93,17 +123,23 @@ func (h *URLShortenerHandler) shortenBatch(w http.ResponseWriter, r *http.Reques
 
 	cmd := command.CreateBatchURLEntryCommand{
 		Entries: make([]command.CreateURLEntryCommand, 0, len(reqDto)),
+		UserID:  userIDFromRequest(r),
 	}
 
 	for _, d := range reqDto {
 		cmd.Entries = append(cmd.Entries, command.CreateURLEntryCommand{
 			OriginalURL:   d.URL,
 			CorrelationID: &d.CorrelationID,
+			Alias:         d.Alias,
+			ExpiresAt:     d.ExpiresAt,
+			MaxClicks:     d.MaxClicks,
+			RedirectCode:  d.RedirectCode,
+			NoIndex:       d.NoIndex,
 		})
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), writeRequestTimeout)
-	urls, err := h.usecases.CreateBatch.Run(ctx, cmd)
+	results, err := h.usecases.CreateBatch.Run(ctx, cmd)
 	defer cancel()
 
 	if err != nil {
@@ -116,16 +152,34 @@ func (h *URLShortenerHandler) shortenBatch(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	res := make([]dto.BatchShortenURLResponse, 0, len(reqDto))
-	for _, u := range urls {
-		res = append(res, dto.BatchShortenURLResponse{
-			CorrelationID: *u.CorrelationID,
-			URL:           h.formatFullURL(u.Hash),
-		})
+	// 201, если создана хотя бы одна ссылка, 200 — если все адреса уже были
+	// сокращены, 422 — если ни одна запись не принята.
+	code := http.StatusUnprocessableEntity
+	res := make([]dto.BatchShortenURLResponse, 0, len(results))
+	for _, item := range results {
+		resItem := dto.BatchShortenURLResponse{
+			CorrelationID: *item.CorrelationID,
+			Status:        string(item.Status),
+		}
+
+		switch item.Status {
+		case model.BatchCreated:
+			code = http.StatusCreated
+			resItem.URL = h.formatFullURL(item.URL.Hash)
+		case model.BatchExisting:
+			if code != http.StatusCreated {
+				code = http.StatusOK
+			}
+			resItem.URL = h.formatFullURL(item.URL.Hash)
+		default:
+			resItem.Error = &dto.BatchItemError{ID: errs.ID(item.Err), Message: item.Err.Error()}
+		}
+
+		res = append(res, resItem)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(code)
 	_ = json.NewEncoder(w).Encode(res)
 }
 
@@ -150,6 +204,17 @@ func (h *URLShortenerHandler) get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Ответ зависит от Accept: JSON с описанием ссылки вместо перехода.
+	w.Header().Add("Vary", "Accept")
+	if wantsJSON(r) {
+		h.publicURLInfo(w, r, hash)
+		return
+	}
+	if r.Method == http.MethodHead {
+		h.head(w, r, hash)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), readRequestTimeout)
 	defer cancel()
 
@@ -157,17 +222,176 @@ func (h *URLShortenerHandler) get(w http.ResponseWriter, r *http.Request) {
 		Hash: hash,
 	})
 
+	if err != nil {
+		handleRedirectError(w, err)
+		return
+	}
+
+	err = h.usecases.RecordClick.Run(r.Context(), command.RecordClickCommand{
+		URLID:     m.ID,
+		Referrer:  r.Referer(),
+		UserAgent: r.UserAgent(),
+		IP:        clientIP(r, h.proxies),
+	})
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to record click", "hash", m.Hash, "error", err)
+	}
+
+	h.redirects.redirect(w, m, time.Now())
+}
+
+// head отвечает теми же заголовками, что и переход, но не засчитывает его
+// и не расходует лимит переходов.
+func (h *URLShortenerHandler) head(w http.ResponseWriter, r *http.Request, hash string) {
+	ctx, cancel := context.WithTimeout(r.Context(), readRequestTimeout)
+	defer cancel()
+
+	info, err := h.usecases.GetInfo.Run(ctx, command.GetURLInfoCommand{Hash: hash})
+	if err == nil && info.Status != model.URLActive {
+		err = errs.GoneError("url " + string(info.Status))
+	}
+	if err != nil {
+		handleRedirectError(w, err)
+		return
+	}
+
+	h.redirects.redirect(w, info.URL, time.Now())
+}
+
+func handleRedirectError(w http.ResponseWriter, err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		w.WriteHeader(http.StatusGatewayTimeout)
+		return
+	}
+
+	var goneErr errs.GoneError
+	if errors.As(err, &goneErr) {
+		helpers.HandleError(w, errs.GoneError("Ссылка удалена или срок её действия истёк"))
+		return
+	}
+
+	helpers.HandleError(w, errs.NotFoundError("Не найден ресурс"))
+}
+
+func (h *URLShortenerHandler) linkStats(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		helpers.HandleError(w, errs.UnauthorizedError("Пользователь не авторизован"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readRequestTimeout)
+	defer cancel()
+
+	hash := chi.URLParam(r, "hash")
+	stats, err := h.usecases.GetLinkStats.Run(ctx, command.GetLinkStatsCommand{
+		UserID: userID,
+		Hash:   hash,
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			w.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+
+		var notFoundErr errs.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			helpers.HandleError(w, errs.NotFoundError("Не найден ресурс"))
+			return
+		}
+
+		h.logger.ErrorContext(r.Context(), err.Error())
+		helpers.HandleError(w, errs.InternalError("Не удалось получить статистику"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(dto.LinkStatsResponse{
+		Hash:           hash,
+		TotalClicks:    stats.Total,
+		UniqueVisitors: stats.UniqueVisitors,
+		Daily:          toClickPoints(stats.Daily),
+		Hourly:         toClickPoints(stats.Hourly),
+	})
+}
+
+func toClickPoints(points []model.ClickPoint) []dto.ClickPointResponse {
+	res := make([]dto.ClickPointResponse, 0, len(points))
+	for _, p := range points {
+		res = append(res, dto.ClickPointResponse{Time: p.Time, Clicks: p.Clicks})
+	}
+	return res
+}
+
+func (h *URLShortenerHandler) userURLs(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		helpers.HandleError(w, errs.UnauthorizedError("Пользователь не авторизован"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readRequestTimeout)
+	defer cancel()
+
+	urls, err := h.usecases.GetByUserID.Run(ctx, command.GetURLsByUserIDCommand{UserID: userID})
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			w.WriteHeader(http.StatusGatewayTimeout)
 			return
 		}
-		helpers.HandleError(w, errs.NotFoundError("Не найден ресурс"))
+		h.logger.ErrorContext(r.Context(), err.Error())
+		helpers.HandleError(w, errs.InternalError("Не удалось получить ссылки"))
+		return
+	}
+
+	if len(urls) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	res := make([]dto.UserURLResponse, 0, len(urls))
+	for _, u := range urls {
+		res = append(res, dto.UserURLResponse{
+			ShortURL:    h.formatFullURL(u.Hash),
+			OriginalURL: u.OriginalURL,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (h *URLShortenerHandler) deleteUserURLs(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		helpers.HandleError(w, errs.UnauthorizedError("Пользователь не авторизован"))
+		return
+	}
+
+	var hashes []string
+	if err := json.NewDecoder(r.Body).Decode(&hashes); err != nil {
+		helpers.HandleError(w, errs.ValidationError(err.Error()))
+		return
+	}
+
+	err := h.usecases.DeleteBatch.Run(r.Context(), command.DeleteURLsCommand{
+		UserID: userID,
+		Hashes: hashes,
+	})
+	if err != nil {
+		var validationErr errs.ValidationError
+		if errors.As(err, &validationErr) {
+			helpers.HandleError(w, validationErr)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), err.Error())
+		helpers.HandleError(w, errs.InternalError("Не удалось поставить ссылки в очередь на удаление"))
 		return
 	}
 
-	w.Header().Set("Location", m.OriginalURL)
-	w.WriteHeader(http.StatusTemporaryRedirect)
+	w.WriteHeader(http.StatusAccepted)
 }
 
 // @TODO: удалить
@@ -187,6 +411,7 @@ func (h *URLShortenerHandler) deprecatedPost(w http.ResponseWriter, r *http.Requ
 
 	m, err := h.usecases.Create.Run(r.Context(), command.CreateURLEntryCommand{
 		OriginalURL: original,
+		UserID:      userIDFromRequest(r),
 	})
 
 	w.Header().Set("Content-Type", "text/plain")
@@ -199,7 +424,7 @@ func (h *URLShortenerHandler) deprecatedPost(w http.ResponseWriter, r *http.Requ
 			return
 		}
 
-		h.logger.Error(err.Error())
+		h.logger.ErrorContext(r.Context(), err.Error())
 		helpers.HandleError(w, errs.ValidationError("Не удалось сформировать ссылку"))
 		return
 	}
@@ -211,3 +436,44 @@ func (h *URLShortenerHandler) deprecatedPost(w http.ResponseWriter, r *http.Requ
 func (h *URLShortenerHandler) formatFullURL(hash string) string {
 	return h.baseURL + hash
 }
+
+// requestError выделяет ошибки в параметрах запроса (код ссылки, срок
+// жизни), чтобы вернуть их клиенту как есть.
+func requestError(err error) error {
+	var takenErr errs.AliasTakenError
+	if errors.As(err, &takenErr) {
+		return takenErr
+	}
+
+	var validationErr errs.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr
+	}
+
+	return nil
+}
+
+// clientIP возвращает адрес клиента. X-Real-IP учитывается, только если
+// запрос пришёл от прокси из подсети proxies: иначе заголовок мог подставить
+// сам клиент.
+func clientIP(r *http.Request, proxies netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		if remote, err := netip.ParseAddr(host); err == nil && proxies.Contains(remote.Unmap()) {
+			return ip
+		}
+	}
+	return host
+}
+
+func userIDFromRequest(r *http.Request) *uuid.UUID {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		return nil
+	}
+	return &userID
+}
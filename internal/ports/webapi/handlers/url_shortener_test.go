@@ -7,43 +7,88 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/amberdance/url-shortener/internal/app/command"
 	"github.com/amberdance/url-shortener/internal/app/usecase"
 	"github.com/amberdance/url-shortener/internal/app/usecase/url"
+	"github.com/amberdance/url-shortener/internal/app/worker"
 	"github.com/amberdance/url-shortener/internal/domain/model"
 	"github.com/amberdance/url-shortener/internal/domain/repository"
 	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
+	"github.com/amberdance/url-shortener/internal/infrastructure/hashgen"
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository/click"
 	infr "github.com/amberdance/url-shortener/internal/infrastructure/repository/url"
 	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
 	"github.com/amberdance/url-shortener/internal/ports/webapi/dto"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/helpers"
+	webmw "github.com/amberdance/url-shortener/internal/ports/webapi/middleware"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const testHost string = "http://127.0.0.1:9999/"
 
-type MockLogger struct{}
+// testProxySubnet содержит RemoteAddr запросов httptest.NewRequest, поэтому
+// X-Real-IP в тестах учитывается.
+const testProxySubnet = "192.0.2.0/24"
+
+var testAliasPolicy = model.AliasPolicy{
+	Charset:   "abcdefghijklmnopqrstuvwxyz0123456789-_",
+	MinLength: 3,
+	MaxLength: 32,
+	Reserved:  []string{"api"},
+}
 
-func (m MockLogger) Debug(_ string, _ ...any) {}
-func (m MockLogger) Info(_ string, _ ...any)  {}
-func (m MockLogger) Error(_ string, _ ...any) {}
-func (m MockLogger) Close() error             { return nil }
+type MockLogger struct{}
 
-var repo repository.URLRepository
+func (m MockLogger) Debug(_ string, _ ...any)                           {}
+func (m MockLogger) Info(_ string, _ ...any)                            {}
+func (m MockLogger) Error(_ string, _ ...any)                           {}
+func (m MockLogger) DebugContext(_ context.Context, _ string, _ ...any) {}
+func (m MockLogger) InfoContext(_ context.Context, _ string, _ ...any)  {}
+func (m MockLogger) ErrorContext(_ context.Context, _ string, _ ...any) {}
+func (m MockLogger) With(_ ...any) shared.Logger                        { return MockLogger{} }
+func (m MockLogger) Close() error                                       { return nil }
+
+var (
+	repo     repository.URLRepository
+	clicks   repository.ClickRepository
+	deleter  *worker.URLDeleter
+	recorder *worker.ClickRecorder
+)
 
 func setupTest() *URLShortenerHandler {
 	var log shared.Logger = MockLogger{}
 
-	repo = infr.NewInMemoryURLRepository(storage.NewInMemoryStorage())
+	st := storage.NewInMemoryStorage()
+	repo = infr.NewInMemoryURLRepository(st)
+	clicks = click.NewInMemoryClickRepository(st)
+	deleter = worker.NewURLDeleter(repo, log)
+	deleter.Start()
+	recorder = worker.NewClickRecorder(clicks, log)
+	recorder.Start()
 	useCases := usecase.URLUseCases{
-		Create:      url.NewCreateURLUseCase(repo),
-		CreateBatch: url.NewBatchCreateURLUseCase(repo),
-		GetByURL:    url.NewGetByHashUseCase(repo),
+		Create:       url.NewCreateURLUseCase(repo, shared.SystemClock{}, testAliasPolicy, hashgen.NewRandomGenerator(8)),
+		CreateBatch:  url.NewBatchCreateURLUseCase(repo, shared.SystemClock{}, testAliasPolicy, hashgen.NewRandomGenerator(8)),
+		GetByURL:     url.NewGetByHashUseCase(repo, shared.SystemClock{}),
+		GetInfo:      url.NewGetURLInfoUseCase(repo, clicks, shared.SystemClock{}),
+		GetByUserID:  url.NewGetByUserIDUseCase(repo),
+		DeleteBatch:  url.NewDeleteBatchUseCase(deleter),
+		RecordClick:  url.NewRecordClickUseCase(recorder, shared.SystemClock{}),
+		GetLinkStats: url.NewGetLinkStatsUseCase(repo, clicks, shared.SystemClock{}),
+		Update:       url.NewUpdateURLUseCase(repo, shared.SystemClock{}),
+		Revisions:    url.NewGetURLRevisionsUseCase(repo),
+		Rollback:     url.NewRollbackURLUseCase(repo, shared.SystemClock{}),
 	}
-	return NewURLShortenerHandler(testHost, useCases, validator.New(), log)
+	return NewURLShortenerHandler(testHost, useCases, validator.New(), RedirectPolicy{}, testProxySubnet, log)
 }
 
 func TestPost_Success(t *testing.T) {
@@ -272,3 +317,355 @@ func TestShorten_409Error(t *testing.T) {
 	json.NewDecoder(res.Body).Decode(&resp)
 	assert.Equal(t, h.baseURL+existing.Hash, resp.URL)
 }
+
+func TestShorten_Alias(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+
+	body := `{"url":"https://hard2code.ru","alias":"my-link"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/shorten", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var resp dto.ShortURLResponse
+	json.NewDecoder(res.Body).Decode(&resp)
+	assert.Equal(t, testHost+"my-link", resp.URL)
+
+	req = httptest.NewRequest(http.MethodGet, "/my-link", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://hard2code.ru", w.Header().Get("Location"))
+}
+
+func TestGet_GoneAfterMaxClicks(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+
+	body := `{"url":"https://hard2code.ru","alias":"once","max_clicks":1}`
+	req := httptest.NewRequest(http.MethodPost, "/api/shorten", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/once", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/once", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusGone, w.Code)
+}
+
+func TestShorten_PastExpiration(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+
+	body := `{"url":"https://hard2code.ru","expires_at":"2000-01-01T00:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/shorten", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestShorten_AliasTaken(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+
+	err := repo.Create(context.Background(), &model.URL{OriginalURL: "https://hard2code.ru", Hash: "my-link"})
+	assert.NoError(t, err)
+
+	body := `{"url":"https://example.com","alias":"my-link"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/shorten", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var resp helpers.ErrorResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	assert.Equal(t, "alias_taken", resp.ID)
+}
+
+func TestShorten_AliasInvalid(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+
+	for _, alias := range []string{"api", "API", "ab", "bad alias", "кириллица"} {
+		body := `{"url":"https://hard2code.ru","alias":"` + alias + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/shorten", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, alias)
+	}
+}
+
+func TestShortenBatch_DuplicateAlias(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+
+	body := `[
+		{"correlation_id":"1","original_url":"https://hard2code.ru","alias":"same"},
+		{"correlation_id":"2","original_url":"https://example.com","alias":"same"}
+	]`
+	req := httptest.NewRequest(http.MethodPost, "/api/shorten/batch", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp []dto.BatchShortenURLResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp, 2)
+	assert.Equal(t, "created", resp[0].Status)
+	assert.Equal(t, "invalid", resp[1].Status)
+	require.NotNil(t, resp[1].Error)
+	assert.Equal(t, "validation_error", resp[1].Error.ID)
+	assert.Empty(t, resp[1].URL)
+}
+
+func TestShortenBatch_PartialSuccess(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+
+	require.NoError(t, repo.Create(context.Background(), &model.URL{OriginalURL: "https://known.example", Hash: "known"}))
+
+	body := `[
+		{"correlation_id":"1","original_url":"https://known.example"},
+		{"correlation_id":"2","original_url":"https://new.example"},
+		{"correlation_id":"3","original_url":""},
+		{"correlation_id":"4","original_url":"https://new.example"}
+	]`
+	req := httptest.NewRequest(http.MethodPost, "/api/shorten/batch", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp []dto.BatchShortenURLResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp, 4)
+
+	assert.Equal(t, "existing", resp[0].Status)
+	assert.Equal(t, testHost+"known", resp[0].URL)
+	assert.Equal(t, "created", resp[1].Status)
+	assert.Equal(t, "invalid", resp[2].Status)
+	assert.Equal(t, "existing", resp[3].Status)
+	assert.Equal(t, resp[1].URL, resp[3].URL)
+	for i, item := range resp {
+		assert.Equal(t, strconv.Itoa(i+1), item.CorrelationID)
+	}
+}
+
+func TestShortenBatch_AllExisting(t *testing.T) {
+	h := setupTest()
+	router := h.Routes()
+
+	require.NoError(t, repo.Create(context.Background(), &model.URL{OriginalURL: "https://known.example", Hash: "known"}))
+
+	body := `[{"correlation_id":"1","original_url":"https://known.example"}]`
+	req := httptest.NewRequest(http.MethodPost, "/api/shorten/batch", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func authorizedRequest(t *testing.T, tm *auth.TokenManager, userID uuid.UUID, method, target string, body io.Reader) *http.Request {
+	token, err := tm.Issue(userID)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(method, target, body)
+	req.AddCookie(&http.Cookie{Name: webmw.AuthCookieName, Value: token})
+	return req
+}
+
+func TestUserURLs_Unauthorized(t *testing.T) {
+	h := setupTest()
+	router := webmw.AuthMiddleware(auth.NewTokenManager("secret"))(h.Routes())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/urls", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestUserURLs_NoContent(t *testing.T) {
+	h := setupTest()
+	tm := auth.NewTokenManager("secret")
+	router := webmw.AuthMiddleware(tm)(h.Routes())
+
+	req := authorizedRequest(t, tm, uuid.New(), http.MethodGet, "/api/user/urls", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+}
+
+func TestUserURLs_Success(t *testing.T) {
+	h := setupTest()
+	tm := auth.NewTokenManager("secret")
+	router := webmw.AuthMiddleware(tm)(h.Routes())
+	userID := uuid.New()
+
+	_, err := h.usecases.Create.Run(context.Background(), command.CreateURLEntryCommand{
+		OriginalURL: "https://hard2code.ru",
+		UserID:      &userID,
+	})
+	assert.NoError(t, err)
+	_, err = h.usecases.Create.Run(context.Background(), command.CreateURLEntryCommand{
+		OriginalURL: "https://google.com",
+	})
+	assert.NoError(t, err)
+
+	req := authorizedRequest(t, tm, userID, http.MethodGet, "/api/user/urls", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var resp []dto.UserURLResponse
+	assert.NoError(t, json.NewDecoder(res.Body).Decode(&resp))
+	assert.Len(t, resp, 1)
+	assert.Equal(t, "https://hard2code.ru", resp[0].OriginalURL)
+	assert.True(t, strings.HasPrefix(resp[0].ShortURL, testHost))
+}
+
+func TestDeleteUserURLs_Accepted(t *testing.T) {
+	h := setupTest()
+	tm := auth.NewTokenManager("secret")
+	router := webmw.AuthMiddleware(tm)(h.Routes())
+	owner, stranger := uuid.New(), uuid.New()
+
+	own, err := h.usecases.Create.Run(context.Background(), command.CreateURLEntryCommand{
+		OriginalURL: "https://hard2code.ru",
+		UserID:      &owner,
+	})
+	assert.NoError(t, err)
+	foreign, err := h.usecases.Create.Run(context.Background(), command.CreateURLEntryCommand{
+		OriginalURL: "https://google.com",
+		UserID:      &stranger,
+	})
+	assert.NoError(t, err)
+
+	body, _ := json.Marshal([]string{own.Hash, foreign.Hash})
+	req := authorizedRequest(t, tm, owner, http.MethodDelete, "/api/user/urls", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusAccepted, res.StatusCode)
+	assert.NoError(t, deleter.Stop(context.Background()))
+
+	tests := []struct {
+		hash string
+		code int
+	}{
+		{own.Hash, http.StatusGone},
+		{foreign.Hash, http.StatusTemporaryRedirect},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/"+tt.hash, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		res := w.Result()
+		res.Body.Close()
+
+		assert.Equal(t, tt.code, res.StatusCode)
+	}
+}
+
+func TestDeleteUserURLs_Unauthorized(t *testing.T) {
+	h := setupTest()
+	router := webmw.AuthMiddleware(auth.NewTokenManager("secret"))(h.Routes())
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/user/urls", bytes.NewBufferString(`["abc"]`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestLinkStats(t *testing.T) {
+	h := setupTest()
+	tm := auth.NewTokenManager("secret")
+	router := webmw.AuthMiddleware(tm)(h.Routes())
+	userID := uuid.New()
+
+	m, err := model.NewURL("https://hard2code.ru", "stats", nil, &userID, time.Now())
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Create(context.Background(), m))
+
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.1.1"} {
+		req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+		req.Header.Set("X-Real-IP", ip)
+		req.Header.Set("User-Agent", "test")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	}
+	assert.NoError(t, recorder.Stop(context.Background()))
+
+	req := authorizedRequest(t, tm, userID, http.MethodGet, "/api/urls/stats/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp dto.LinkStatsResponse
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, 3, resp.TotalClicks)
+	assert.Equal(t, 2, resp.UniqueVisitors, "адреса из одной /24 неразличимы")
+	assert.Len(t, resp.Daily, 1)
+	assert.Len(t, resp.Hourly, 1)
+	assert.Equal(t, 3, resp.Hourly[0].Clicks)
+
+	req = authorizedRequest(t, tm, uuid.New(), http.MethodGet, "/api/urls/stats/stats", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestClientIP(t *testing.T) {
+	proxies := netip.MustParsePrefix("10.0.0.0/8")
+
+	tests := []struct {
+		name     string
+		remote   string
+		realIP   string
+		expected string
+	}{
+		{"without header", "203.0.113.5:4321", "", "203.0.113.5"},
+		{"header from trusted proxy", "10.1.2.3:4321", "198.51.100.7", "198.51.100.7"},
+		{"header from client", "203.0.113.5:4321", "198.51.100.7", "203.0.113.5"},
+		{"remote without port", "203.0.113.5", "", "203.0.113.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/x", nil)
+			r.RemoteAddr = tt.remote
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+			assert.Equal(t, tt.expected, clientIP(r, proxies))
+		})
+	}
+}
@@ -9,12 +9,21 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
+// TraceIDHeader — заголовок ответа с идентификатором трассы запроса.
+// Его выставляет TracingMiddleware до вызова обработчика.
+const TraceIDHeader = "X-Trace-Id"
+
 type ErrorResponse struct {
 	ID      string `json:"id"`
 	Message string `json:"message"`
+	TraceID string `json:"trace_id,omitempty"`
 }
 
+// HandleError отвечает клиенту ошибкой err. Внутренние и непредвиденные
+// ошибки дополнительно пишутся в лог вместе с идентификатором трассы.
 func HandleError(w http.ResponseWriter, err error) {
+	traceID := w.Header().Get(TraceIDHeader)
+
 	var code int
 	var errorID string
 
@@ -27,14 +36,20 @@ func HandleError(w http.ResponseWriter, err error) {
 		code, errorID = http.StatusBadRequest, e.ID()
 	case errs.InternalError:
 		code, errorID = http.StatusInternalServerError, e.ID()
-		log.Println("Internal error:", err.Error())
+		log.Println("Internal error:", err.Error(), "trace_id:", traceID)
 	case errs.UnauthorizedError:
 		code, errorID = http.StatusUnauthorized, e.ID()
 	case errs.DuplicateEntryError:
 		code, errorID = http.StatusConflict, e.ID()
+	case errs.AliasTakenError:
+		code, errorID = http.StatusConflict, e.ID()
+	case errs.HashConflictError:
+		code, errorID = http.StatusConflict, e.ID()
+	case errs.GoneError:
+		code, errorID = http.StatusGone, e.ID()
 	default:
 		code, errorID = http.StatusInternalServerError, "internal_error"
-		log.Println("Unexpected error:", err.Error())
+		log.Println("Unexpected error:", err.Error(), "trace_id:", traceID)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -42,6 +57,7 @@ func HandleError(w http.ResponseWriter, err error) {
 	_ = json.NewEncoder(w).Encode(ErrorResponse{
 		ID:      errorID,
 		Message: err.Error(),
+		TraceID: traceID,
 	})
 }
 
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+type accessLogKey struct{}
+
+// accessLogEntry собирает сведения, которые становятся известны глубже по
+// цепочке middleware, например пользователя после AuthMiddleware.
+type accessLogEntry struct {
+	userID *uuid.UUID
+}
+
+// AccessLogMiddleware пишет запись о каждом запросе через l: метод, путь,
+// код ответа, размер тела, длительность, request id и пользователя.
+// Ответы 5xx пишутся с уровнем error. Должен стоять после RequestID и
+// TracingMiddleware, чтобы запись получила их идентификаторы.
+func AccessLogMiddleware(l shared.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			started := time.Now()
+			entry := &accessLogEntry{}
+			ctx := context.WithValue(r.Context(), accessLogKey{}, entry)
+			ww := chimw.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			args := []any{
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", ww.BytesWritten(),
+				"duration_ms", float64(time.Since(started).Microseconds()) / 1000,
+				"request_id", chimw.GetReqID(r.Context()),
+			}
+			if entry.userID != nil {
+				args = append(args, "user_id", entry.userID.String())
+			}
+
+			if status >= http.StatusInternalServerError {
+				l.ErrorContext(r.Context(), "http request", args...)
+			} else {
+				l.InfoContext(r.Context(), "http request", args...)
+			}
+		})
+	}
+}
+
+// annotateUser сообщает AccessLogMiddleware пользователя запроса.
+func annotateUser(ctx context.Context, userID uuid.UUID) {
+	if entry, ok := ctx.Value(accessLogKey{}).(*accessLogEntry); ok {
+		entry.userID = &userID
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logRecord struct {
+	level string
+	msg   string
+	attrs map[string]any
+}
+
+type recordingLogger struct {
+	records *[]logRecord
+}
+
+func (l recordingLogger) add(level, msg string, args []any) {
+	attrs := make(map[string]any)
+	for i := 0; i+1 < len(args); i += 2 {
+		attrs[args[i].(string)] = args[i+1]
+	}
+	*l.records = append(*l.records, logRecord{level: level, msg: msg, attrs: attrs})
+}
+
+func (l recordingLogger) Debug(msg string, args ...any) { l.add("debug", msg, args) }
+func (l recordingLogger) Info(msg string, args ...any)  { l.add("info", msg, args) }
+func (l recordingLogger) Error(msg string, args ...any) { l.add("error", msg, args) }
+func (l recordingLogger) DebugContext(_ context.Context, msg string, args ...any) {
+	l.add("debug", msg, args)
+}
+func (l recordingLogger) InfoContext(_ context.Context, msg string, args ...any) {
+	l.add("info", msg, args)
+}
+func (l recordingLogger) ErrorContext(_ context.Context, msg string, args ...any) {
+	l.add("error", msg, args)
+}
+func (l recordingLogger) With(_ ...any) shared.Logger { return l }
+func (l recordingLogger) Close() error                { return nil }
+
+func TestAccessLogMiddleware(t *testing.T) {
+	var records []logRecord
+	tm := auth.NewTokenManager("secret")
+
+	handler := chimw.RequestID(AccessLogMiddleware(recordingLogger{&records})(
+		AuthMiddleware(tm)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/fail" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte("hello"))
+		})),
+	))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	require.Len(t, records, 2)
+	ok := records[0]
+	assert.Equal(t, "info", ok.level)
+	assert.Equal(t, "GET", ok.attrs["method"])
+	assert.Equal(t, "/ok", ok.attrs["path"])
+	assert.Equal(t, http.StatusOK, ok.attrs["status"])
+	assert.Equal(t, 5, ok.attrs["bytes"])
+	assert.NotEmpty(t, ok.attrs["request_id"])
+	assert.NotEmpty(t, ok.attrs["user_id"])
+
+	assert.Equal(t, "error", records[1].level)
+	assert.Equal(t, http.StatusInternalServerError, records[1].attrs["status"])
+}
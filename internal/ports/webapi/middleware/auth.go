@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
+	"github.com/amberdance/url-shortener/internal/ports/webapi/helpers"
+	"github.com/google/uuid"
+)
+
+const AuthCookieName = "auth_token"
+
+func AuthMiddleware(tm *auth.TokenManager) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie(AuthCookieName)
+			if err == nil {
+				userID, parseErr := tm.Parse(cookie.Value)
+				if parseErr == nil {
+					annotateUser(r.Context(), userID)
+					next.ServeHTTP(w, r.WithContext(auth.WithUserID(r.Context(), userID)))
+					return
+				}
+
+				// Куку с чужой подписью сбрасываем, чтобы следующий запрос
+				// получил новый идентификатор, а не 401 снова.
+				var unauthorized errs.UnauthorizedError
+				if errors.As(parseErr, &unauthorized) {
+					http.SetCookie(w, &http.Cookie{
+						Name:     AuthCookieName,
+						Value:    "",
+						Path:     "/",
+						MaxAge:   -1,
+						HttpOnly: true,
+						Secure:   r.TLS != nil,
+						SameSite: http.SameSiteLaxMode,
+					})
+					helpers.HandleError(w, unauthorized)
+					return
+				}
+			}
+
+			userID := uuid.New()
+			token, err := tm.Issue(userID)
+			if err != nil {
+				helpers.HandleError(w, errs.InternalError("Не удалось выпустить токен"))
+				return
+			}
+
+			http.SetCookie(w, &http.Cookie{
+				Name:     AuthCookieName,
+				Value:    token,
+				Path:     "/",
+				MaxAge:   int(auth.TokenTTL.Seconds()),
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteLaxMode,
+			})
+
+			annotateUser(r.Context(), userID)
+			next.ServeHTTP(w, r.WithContext(auth.WithIssuedUserID(r.Context(), userID)))
+		})
+	}
+}
+
+// RequireAuthMiddleware пропускает только запросы, пришедшие с действующей
+// идентификационной кукой. Должен стоять после AuthMiddleware.
+func RequireAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := auth.AuthenticatedUserID(r.Context()); !ok {
+			helpers.HandleError(w, errs.UnauthorizedError("Пользователь не авторизован"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amberdance/url-shortener/internal/infrastructure/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSecret = "test-secret"
+
+func captureUserID(dst *uuid.UUID) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*dst, _ = auth.UserIDFromContext(r.Context())
+	})
+}
+
+func TestAuthMiddleware_IssuesCookie(t *testing.T) {
+	var userID uuid.UUID
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	AuthMiddleware(auth.NewTokenManager(testSecret))(captureUserID(&userID)).ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.NotEqual(t, uuid.Nil, userID)
+	assert.Len(t, res.Cookies(), 1)
+	assert.Equal(t, AuthCookieName, res.Cookies()[0].Name)
+}
+
+func TestAuthMiddleware_ValidCookie(t *testing.T) {
+	tm := auth.NewTokenManager(testSecret)
+	expected := uuid.New()
+	token, err := tm.Issue(expected)
+	assert.NoError(t, err)
+
+	var userID uuid.UUID
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: AuthCookieName, Value: token})
+	w := httptest.NewRecorder()
+
+	AuthMiddleware(tm)(captureUserID(&userID)).ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, expected, userID)
+	assert.Empty(t, res.Cookies())
+}
+
+func TestAuthMiddleware_TamperedCookie(t *testing.T) {
+	token, err := auth.NewTokenManager("other-secret").Issue(uuid.New())
+	assert.NoError(t, err)
+
+	called := false
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: AuthCookieName, Value: token})
+	w := httptest.NewRecorder()
+
+	AuthMiddleware(auth.NewTokenManager(testSecret))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})).ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	if assert.Len(t, res.Cookies(), 1) {
+		assert.Equal(t, AuthCookieName, res.Cookies()[0].Name)
+		assert.Negative(t, res.Cookies()[0].MaxAge, "поддельная кука сбрасывается")
+	}
+}
+
+func TestAuthMiddleware_SecureCookieOverTLS(t *testing.T) {
+	var userID uuid.UUID
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/", nil)
+	w := httptest.NewRecorder()
+
+	AuthMiddleware(auth.NewTokenManager(testSecret))(captureUserID(&userID)).ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	if assert.Len(t, res.Cookies(), 1) {
+		assert.True(t, res.Cookies()[0].Secure)
+	}
+}
+
+func TestAuthMiddleware_MalformedCookie(t *testing.T) {
+	var userID uuid.UUID
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: AuthCookieName, Value: "garbage"})
+	w := httptest.NewRecorder()
+
+	AuthMiddleware(auth.NewTokenManager(testSecret))(captureUserID(&userID)).ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.NotEqual(t, uuid.Nil, userID)
+	assert.Len(t, res.Cookies(), 1)
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// TrustedSubnetMiddleware пропускает только запросы, у которых X-Real-IP
+// входит в доверенную подсеть. Пустая подсеть закрывает доступ для всех.
+func TrustedSubnetMiddleware(cidr string) func(http.Handler) http.Handler {
+	prefix, err := netip.ParsePrefix(cidr)
+	trusted := cidr != "" && err == nil
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !trusted {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			ip, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP")))
+			if err != nil || !prefix.Contains(ip.Unmap()) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
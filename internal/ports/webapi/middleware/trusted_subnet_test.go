@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrustedSubnetMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		subnet string
+		realIP string
+		code   int
+	}{
+		{"trusted ip", "192.168.1.0/24", "192.168.1.15", http.StatusOK},
+		{"untrusted ip", "192.168.1.0/24", "10.0.0.1", http.StatusForbidden},
+		{"missing header", "192.168.1.0/24", "", http.StatusForbidden},
+		{"malformed header", "192.168.1.0/24", "not-an-ip", http.StatusForbidden},
+		{"empty subnet", "", "192.168.1.15", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			w := httptest.NewRecorder()
+			TrustedSubnetMiddleware(tt.subnet)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})).ServeHTTP(w, req)
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.code, res.StatusCode)
+		})
+	}
+}
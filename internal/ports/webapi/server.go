@@ -2,8 +2,10 @@ package webapi
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,7 +13,9 @@ import (
 	"time"
 
 	"github.com/amberdance/url-shortener/internal/app"
+	"github.com/amberdance/url-shortener/internal/app/worker"
 	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/infrastructure/certs"
 	"github.com/amberdance/url-shortener/internal/ports/webapi/handlers"
 	webmw "github.com/amberdance/url-shortener/internal/ports/webapi/middleware"
 
@@ -21,24 +25,48 @@ import (
 )
 
 type Server struct {
-	httpServer *http.Server
-	logger     shared.Logger
+	httpServer     *http.Server
+	redirectServer *http.Server
+	logger         shared.Logger
+	deleter        *worker.URLDeleter
+	enableHTTPS    bool
+	certFile       string
+	keyFile        string
 }
 
 func NewServer(a *app.App) *Server {
-	router := buildRoutes(a)
-	handler := cors.AllowAll().Handler(router)
+	cfg := a.Config()
 	httpSrv := &http.Server{
-		Addr:    a.Config().Address,
-		Handler: handler,
+		Addr:    cfg.Address,
+		Handler: NewHandler(a),
 	}
 
-	return &Server{httpServer: httpSrv, logger: a.Logger()}
+	s := &Server{
+		httpServer:  httpSrv,
+		logger:      a.Logger(),
+		deleter:     a.Container().URLDeleter,
+		enableHTTPS: cfg.EnableHTTPS,
+		certFile:    cfg.TLSCertFile,
+		keyFile:     cfg.TLSKeyFile,
+	}
+
+	if cfg.EnableHTTPS && cfg.HTTPRedirect != "" {
+		s.redirectServer = &http.Server{
+			Addr:    cfg.HTTPRedirect,
+			Handler: httpsRedirectHandler(cfg.Address),
+		}
+	}
+
+	return s
 }
 
 func (s *Server) Run(ctx context.Context) error {
 	l := s.logger
-	l.Info(fmt.Sprintf("Server is running on %s", s.httpServer.Addr))
+	if s.enableHTTPS {
+		l.Info(fmt.Sprintf("Server is running on %s (HTTPS)", s.httpServer.Addr))
+	} else {
+		l.Info(fmt.Sprintf("Server is running on %s", s.httpServer.Addr))
+	}
 
 	idleConnsClosed := make(chan struct{})
 
@@ -59,10 +87,27 @@ func (s *Server) Run(ctx context.Context) error {
 		if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
 			l.Error("HTTP server shutdown: %v", err)
 		}
+		if s.redirectServer != nil {
+			if err := s.redirectServer.Shutdown(shutdownCtx); err != nil {
+				l.Error("HTTP redirect server shutdown", "error", err)
+			}
+		}
+		if err := s.deleter.Stop(shutdownCtx); err != nil {
+			l.Error("URL deleter shutdown", "error", err)
+		}
 		close(idleConnsClosed)
 	}()
 
-	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	if s.redirectServer != nil {
+		go func() {
+			l.Info(fmt.Sprintf("Redirecting HTTP from %s to HTTPS", s.redirectServer.Addr))
+			if err := s.redirectServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				l.Error("HTTP redirect server", "error", err)
+			}
+		}()
+	}
+
+	if err := s.listenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 	<-idleConnsClosed
@@ -70,29 +115,107 @@ func (s *Server) Run(ctx context.Context) error {
 	return nil
 }
 
+func (s *Server) listenAndServe() error {
+	if !s.enableHTTPS {
+		return s.httpServer.ListenAndServe()
+	}
+
+	s.httpServer.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if s.certFile != "" {
+		return s.httpServer.ListenAndServeTLS(s.certFile, s.keyFile)
+	}
+
+	host, _, _ := net.SplitHostPort(s.httpServer.Addr)
+	cert, err := certs.GenerateSelfSigned(host)
+	if err != nil {
+		return fmt.Errorf("failed to generate self-signed certificate: %w", err)
+	}
+	s.logger.Info("TLS certificate is not configured, using self-signed certificate")
+	s.httpServer.TLSConfig.Certificates = []tls.Certificate{cert}
+
+	return s.httpServer.ListenAndServeTLS("", "")
+}
+
 func (s *Server) Stop(ctx context.Context) error {
 	s.logger.Info("Stopping server manually")
 	return s.httpServer.Shutdown(ctx)
 }
 
+// NewHandler собирает HTTP-обработчик сервиса без запуска сервера.
+func NewHandler(a *app.App) http.Handler {
+	return cors.AllowAll().Handler(buildRoutes(a))
+}
+
+func httpsRedirectHandler(httpsAddress string) http.Handler {
+	_, port, _ := net.SplitHostPort(httpsAddress)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
+
 func buildRoutes(a *app.App) *chi.Mux {
 	router := chi.NewRouter()
 	router.Use(middleware.RequestID)
-	router.Use(middleware.Logger)
+	router.Use(webmw.TracingMiddleware(a.TracerProvider()))
+	router.Use(webmw.MetricsMiddleware(a.Metrics()))
+	router.Use(webmw.AccessLogMiddleware(a.Logger()))
 	router.Use(middleware.Recoverer)
 
 	router.Mount("/health", handlers.NewHealthcheckHandler().Routes())
+	router.Handle("/metrics", a.Metrics().Handler())
 	router.Mount("/ping", handlers.NewPingHandler(a.Pinger()).Routes())
+	router.Mount("/api/internal/stats", handlers.NewStatsHandler(
+		a.Config().TrustedSubnet,
+		a.Container().UseCases.URL.GetStats,
+		a.Logger()).Routes(),
+	)
+
+	exports := handlers.NewExportHandler(a.Config().BaseURL, a.Container().UseCases.URL.Export, a.Logger())
+	router.Mount("/api/internal/urls/export", exports.AdminRoutes(a.Config().TrustedSubnet))
+
+	// Импорт и выгрузка отдают результат потоком, поэтому обходятся без
+	// GzipCompressMiddleware, которая буферизует ответ целиком.
+	router.Group(func(r chi.Router) {
+		r.Use(webmw.GzipDecompressMiddleware)
+		r.Use(webmw.AuthMiddleware(a.Container().TokenManager))
+
+		r.Mount("/api/user/urls/export", exports.UserRoutes())
+
+		r.Mount("/api/shorten/import", handlers.NewImportHandler(
+			a.Config().BaseURL,
+			a.Container().UseCases.URL.CreateBatch,
+			int64(a.Config().ImportMaxBodyMB)<<20,
+			a.Config().ImportChunkSize,
+			a.Logger()).Routes(),
+		)
+	})
 
 	router.Group(func(r chi.Router) {
 		r.Use(webmw.JSONMiddleware)
 		r.Use(webmw.GzipDecompressMiddleware)
 		r.Use(webmw.GzipCompressMiddleware)
+		r.Use(webmw.AuthMiddleware(a.Container().TokenManager))
 
 		r.Mount("/", handlers.NewURLShortenerHandler(
 			a.Config().BaseURL,
 			a.Container().UseCases.URL,
 			a.Container().Validator,
+			handlers.RedirectPolicy{
+				Code:    a.Config().RedirectCode,
+				MaxAge:  a.Config().RedirectCacheMaxAge,
+				NoIndex: a.Config().RedirectNoIndex,
+			},
+			a.Config().TrustedSubnet,
 			a.Logger()).Routes(),
 		)
 	})
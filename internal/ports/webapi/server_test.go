@@ -0,0 +1,34 @@
+package webapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		target   string
+		location string
+	}{
+		{"custom port", "0.0.0.0:8443", "http://example.com:8080/abc?x=1", "https://example.com:8443/abc?x=1"},
+		{"default port", ":443", "http://example.com/abc", "https://example.com/abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			httpsRedirectHandler(tt.address).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			res := w.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, http.StatusPermanentRedirect, res.StatusCode)
+			assert.Equal(t, tt.location, res.Header.Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package shortener
+
+import (
+	"context"
+
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+)
+
+// Logger — логгер, который принимает WithLogger. Если он дополнительно
+// реализует методы *Context и With внутреннего логгера сервиса, они
+// используются напрямую; иначе записи с контекстом пишутся без него.
+type Logger interface {
+	Debug(message string, args ...any)
+	Info(message string, args ...any)
+	Error(message string, args ...any)
+	Close() error
+}
+
+// adaptLogger дополняет l до shared.Logger.
+func adaptLogger(l Logger) shared.Logger {
+	if full, ok := l.(shared.Logger); ok {
+		return full
+	}
+	return loggerAdapter{base: l, owner: true}
+}
+
+type loggerAdapter struct {
+	base  Logger
+	args  []any
+	owner bool
+}
+
+func (a loggerAdapter) Debug(message string, args ...any) { a.base.Debug(message, a.with(args)...) }
+func (a loggerAdapter) Info(message string, args ...any)  { a.base.Info(message, a.with(args)...) }
+func (a loggerAdapter) Error(message string, args ...any) { a.base.Error(message, a.with(args)...) }
+
+func (a loggerAdapter) DebugContext(_ context.Context, message string, args ...any) {
+	a.Debug(message, args...)
+}
+
+func (a loggerAdapter) InfoContext(_ context.Context, message string, args ...any) {
+	a.Info(message, args...)
+}
+
+func (a loggerAdapter) ErrorContext(_ context.Context, message string, args ...any) {
+	a.Error(message, args...)
+}
+
+func (a loggerAdapter) With(args ...any) shared.Logger {
+	return loggerAdapter{base: a.base, args: a.with(args)}
+}
+
+// Close закрывает исходный логгер; производные от With ничего не закрывают.
+func (a loggerAdapter) Close() error {
+	if !a.owner {
+		return nil
+	}
+	return a.base.Close()
+}
+
+func (a loggerAdapter) with(args []any) []any {
+	if len(a.args) == 0 {
+		return args
+	}
+	return append(append(make([]any, 0, len(a.args)+len(args)), a.args...), args...)
+}
@@ -0,0 +1,39 @@
+package shortener
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingLogger struct {
+	lines  *[][]any
+	closed *int
+}
+
+func (l recordingLogger) Debug(m string, args ...any) {
+	*l.lines = append(*l.lines, append([]any{m}, args...))
+}
+func (l recordingLogger) Info(m string, args ...any)  { l.Debug(m, args...) }
+func (l recordingLogger) Error(m string, args ...any) { l.Debug(m, args...) }
+func (l recordingLogger) Close() error                { *l.closed++; return nil }
+
+func TestAdaptLogger_AddsContextMethodsAndWith(t *testing.T) {
+	var (
+		lines  [][]any
+		closed int
+	)
+	l := adaptLogger(recordingLogger{lines: &lines, closed: &closed})
+
+	l.ErrorContext(context.Background(), "a", "k", 1)
+	child := l.With("request_id", "r1")
+	child.InfoContext(context.Background(), "b", "k", 2)
+
+	assert.Equal(t, [][]any{{"a", "k", 1}, {"b", "request_id", "r1", "k", 2}}, lines)
+
+	assert.NoError(t, child.Close())
+	assert.Zero(t, closed, "производный логгер не закрывает исходный")
+	assert.NoError(t, l.Close())
+	assert.Equal(t, 1, closed)
+}
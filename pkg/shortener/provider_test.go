@@ -0,0 +1,214 @@
+package shortener_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/pkg/shortener"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// mapProvider — репозиторий, собранный только из типов pkg/shortener, как
+// его написал бы код вне модуля. Если интерфейсу понадобится внутренний
+// тип без псевдонима, тест перестанет компилироваться.
+type mapProvider struct {
+	mu     sync.Mutex
+	urls   map[string]*shortener.URL
+	clicks []*shortener.Click
+}
+
+var _ shortener.RepositoryProvider = (*mapProvider)(nil)
+
+func newMapProvider() *mapProvider {
+	return &mapProvider{urls: make(map[string]*shortener.URL)}
+}
+
+func (p *mapProvider) URLRepository() shortener.URLRepository     { return mapURLs{p} }
+func (p *mapProvider) ClickRepository() shortener.ClickRepository { return mapClicks{p} }
+
+type mapURLs struct{ p *mapProvider }
+
+func (r mapURLs) Create(_ context.Context, u *shortener.URL) error {
+	r.p.mu.Lock()
+	defer r.p.mu.Unlock()
+
+	if _, ok := r.p.urls[u.Hash]; ok {
+		return shortener.HashConflictError("hash already exists")
+	}
+	for _, existing := range r.p.urls {
+		if existing.OriginalURL == u.OriginalURL && !existing.IsDeleted {
+			return shortener.DuplicateEntryError("url already exists")
+		}
+	}
+	r.p.urls[u.Hash] = u
+	return nil
+}
+
+func (r mapURLs) CreateBatch(ctx context.Context, urls []*shortener.URL) ([]error, error) {
+	results := make([]error, len(urls))
+	for i, u := range urls {
+		results[i] = r.Create(ctx, u)
+	}
+	return results, nil
+}
+
+func (r mapURLs) FindByHash(_ context.Context, hash string) (*shortener.URL, error) {
+	r.p.mu.Lock()
+	defer r.p.mu.Unlock()
+
+	u, ok := r.p.urls[hash]
+	if !ok {
+		return nil, shortener.NotFoundError("url not found")
+	}
+	return u, nil
+}
+
+func (r mapURLs) FindByOriginalURL(_ context.Context, original string) (*shortener.URL, error) {
+	r.p.mu.Lock()
+	defer r.p.mu.Unlock()
+
+	for _, u := range r.p.urls {
+		if u.OriginalURL == original && !u.IsDeleted {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r mapURLs) FindByUserID(_ context.Context, userID uuid.UUID) ([]*shortener.URL, error) {
+	r.p.mu.Lock()
+	defer r.p.mu.Unlock()
+
+	var urls []*shortener.URL
+	for _, u := range r.p.urls {
+		if u.UserID != nil && *u.UserID == userID && !u.IsDeleted {
+			urls = append(urls, u)
+		}
+	}
+	return urls, nil
+}
+
+func (r mapURLs) FindPage(_ context.Context, q shortener.URLPageQuery) ([]*shortener.URL, error) {
+	r.p.mu.Lock()
+	defer r.p.mu.Unlock()
+
+	var page []*shortener.URL
+	for _, u := range r.p.urls {
+		if !u.IsDeleted && strings.Compare(u.ID.String(), q.After.String()) > 0 &&
+			(q.UserID == nil || (u.UserID != nil && *u.UserID == *q.UserID)) {
+			page = append(page, u)
+		}
+	}
+	slices.SortFunc(page, func(a, b *shortener.URL) int { return strings.Compare(a.ID.String(), b.ID.String()) })
+	if len(page) > q.Limit {
+		page = page[:q.Limit]
+	}
+	return page, nil
+}
+
+func (r mapURLs) DeleteBatch(_ context.Context, byUser map[uuid.UUID][]string) error {
+	r.p.mu.Lock()
+	defer r.p.mu.Unlock()
+
+	for userID, hashes := range byUser {
+		for _, h := range hashes {
+			if u, ok := r.p.urls[h]; ok && u.UserID != nil && *u.UserID == userID {
+				u.IsDeleted = true
+			}
+		}
+	}
+	return nil
+}
+
+func (r mapURLs) Update(_ context.Context, u *shortener.URL, _ *shortener.URLRevision) error {
+	r.p.mu.Lock()
+	defer r.p.mu.Unlock()
+
+	if _, ok := r.p.urls[u.Hash]; !ok {
+		return shortener.NotFoundError("url not found")
+	}
+	r.p.urls[u.Hash] = u
+	return nil
+}
+
+func (r mapURLs) FindRevisions(_ context.Context, _ uuid.UUID) ([]*shortener.URLRevision, error) {
+	return nil, nil
+}
+
+func (r mapURLs) CountURLs(_ context.Context) (int, error) {
+	r.p.mu.Lock()
+	defer r.p.mu.Unlock()
+
+	return len(r.p.urls), nil
+}
+
+func (r mapURLs) CountUsers(_ context.Context) (int, error) { return 0, nil }
+
+func (r mapURLs) RegisterClick(_ context.Context, hash string) (bool, error) {
+	r.p.mu.Lock()
+	defer r.p.mu.Unlock()
+
+	u, ok := r.p.urls[hash]
+	if !ok {
+		return false, shortener.NotFoundError("url not found")
+	}
+	if u.MaxClicks != nil && u.Clicks >= *u.MaxClicks {
+		return false, nil
+	}
+	u.Clicks++
+	return true, nil
+}
+
+func (r mapURLs) PurgeExpired(_ context.Context, _ time.Time) (int, error) { return 0, nil }
+
+type mapClicks struct{ p *mapProvider }
+
+func (r mapClicks) SaveBatch(_ context.Context, clicks []*shortener.Click) error {
+	r.p.mu.Lock()
+	defer r.p.mu.Unlock()
+
+	r.p.clicks = append(r.p.clicks, clicks...)
+	return nil
+}
+
+func (r mapClicks) Stats(ctx context.Context, q shortener.ClickStatsQuery) (*shortener.ClickStats, error) {
+	counts, err := r.CountByURL(ctx, []uuid.UUID{q.URLID})
+	if err != nil {
+		return nil, err
+	}
+	return &shortener.ClickStats{Total: counts[q.URLID], Daily: []shortener.ClickPoint{}}, nil
+}
+
+func (r mapClicks) CountByURL(_ context.Context, urlIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	r.p.mu.Lock()
+	defer r.p.mu.Unlock()
+
+	counts := make(map[uuid.UUID]int)
+	for _, c := range r.p.clicks {
+		if slices.Contains(urlIDs, c.URLID) {
+			counts[c.URLID]++
+		}
+	}
+	return counts, nil
+}
+
+func TestWithRepositoryProvider_ExternalImplementation(t *testing.T) {
+	p := newMapProvider()
+	s := newShortener(t, "http://ext.local", p, fixedClock(time.Now()))
+
+	short := shorten(t, s.Handler(), "https://hard2code.ru")
+	hash := strings.TrimPrefix(short, "http://ext.local/")
+
+	w := httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+hash, nil))
+
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://hard2code.ru", w.Header().Get("Location"))
+}
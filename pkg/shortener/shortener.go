@@ -0,0 +1,124 @@
+// Package shortener позволяет встроить сервис сокращения ссылок в другое
+// приложение или тест: несколько экземпляров могут работать в одном
+// процессе, а разбор флагов остаётся на стороне вызывающего кода.
+package shortener
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/amberdance/url-shortener/internal/app"
+	"github.com/amberdance/url-shortener/internal/config"
+	"github.com/amberdance/url-shortener/internal/domain/errs"
+	"github.com/amberdance/url-shortener/internal/domain/model"
+	"github.com/amberdance/url-shortener/internal/domain/repository"
+	"github.com/amberdance/url-shortener/internal/domain/shared"
+	"github.com/amberdance/url-shortener/internal/ports/grpcapi"
+	"github.com/amberdance/url-shortener/internal/ports/webapi"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	Config             = config.Config
+	Clock              = shared.Clock
+	HashGenerator      = shared.HashGenerator
+	URL                = model.URL
+	Stats              = model.Stats
+	URLRepository      = repository.URLRepository
+	ClickRepository    = repository.ClickRepository
+	RepositoryProvider = app.RepositoryProvider
+	Option             = app.Option
+)
+
+// Типы, через которые работают методы репозиториев: собственная реализация
+// RepositoryProvider не должна зависеть от внутренних пакетов.
+type (
+	URLPageQuery    = model.URLPageQuery
+	URLRevision     = model.URLRevision
+	Click           = model.Click
+	ClickPoint      = model.ClickPoint
+	ClickStats      = model.ClickStats
+	ClickStatsQuery = model.ClickStatsQuery
+)
+
+// Ошибки, которые репозиторий возвращает по контракту URLRepository.
+type (
+	DuplicateEntryError = errs.DuplicateEntryError
+	HashConflictError   = errs.HashConflictError
+	NotFoundError       = errs.NotFoundError
+)
+
+func WithLogger(l Logger) Option { return app.WithLogger(adaptLogger(l)) }
+
+func WithRepositoryProvider(p RepositoryProvider) Option { return app.WithRepositoryProvider(p) }
+
+func WithClock(c Clock) Option { return app.WithClock(c) }
+
+func WithHashGenerator(g HashGenerator) Option { return app.WithHashGenerator(g) }
+
+// WithTracerProvider отдаёт span'ы экземпляра в tp вместо провайдера,
+// который строится по Config.TracingExporter.
+func WithTracerProvider(tp trace.TracerProvider) Option { return app.WithTracerProvider(tp) }
+
+// DefaultConfig возвращает конфигурацию со значениями по умолчанию.
+func DefaultConfig() Config { return config.Default() }
+
+type Shortener struct {
+	app     *app.App
+	handler http.Handler
+}
+
+func New(cfg Config, opts ...Option) (*Shortener, error) {
+	if err := cfg.Finalize(); err != nil {
+		return nil, err
+	}
+
+	a, err := app.New(&cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Shortener{app: a, handler: webapi.NewHandler(a)}, nil
+}
+
+func (s *Shortener) Config() Config { return *s.app.Config() }
+
+func (s *Shortener) Handler() http.Handler { return s.handler }
+
+// TracerProvider возвращает провайдер трасс экземпляра. Глобальным в otel
+// он не становится: это решает вызывающий код.
+func (s *Shortener) TracerProvider() trace.TracerProvider { return s.app.TracerProvider() }
+
+// Run запускает HTTP-сервер и, если задан GRPCAddress, gRPC-сервер.
+// Оба останавливаются по отмене ctx или по SIGINT/SIGTERM.
+func (s *Shortener) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	grpcErr := make(chan error, 1)
+	if s.app.Config().GRPCAddress != "" {
+		grpcSrv := grpcapi.NewServer(s.app)
+		go func() {
+			err := grpcSrv.Run(ctx)
+			if err != nil {
+				cancel()
+			}
+			grpcErr <- err
+		}()
+	} else {
+		grpcErr <- nil
+	}
+
+	httpErr := webapi.NewServer(s.app).Run(ctx)
+	cancel()
+
+	if err := <-grpcErr; err != nil {
+		return err
+	}
+	return httpErr
+}
+
+// Close дожидается фоновых задач и освобождает хранилище и логгер.
+func (s *Shortener) Close() {
+	s.app.Close()
+}
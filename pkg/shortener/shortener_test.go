@@ -0,0 +1,91 @@
+package shortener_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amberdance/url-shortener/internal/infrastructure/repository"
+	"github.com/amberdance/url-shortener/internal/infrastructure/storage"
+	"github.com/amberdance/url-shortener/pkg/shortener"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(_ string, _ ...any) {}
+func (nopLogger) Info(_ string, _ ...any)  {}
+func (nopLogger) Error(_ string, _ ...any) {}
+func (nopLogger) Close() error             { return nil }
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func newShortener(t *testing.T, baseURL string, p shortener.RepositoryProvider, c shortener.Clock) *shortener.Shortener {
+	cfg := shortener.DefaultConfig()
+	cfg.BaseURL = baseURL
+
+	s, err := shortener.New(cfg,
+		shortener.WithLogger(nopLogger{}),
+		shortener.WithRepositoryProvider(p),
+		shortener.WithClock(c),
+	)
+	require.NoError(t, err)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func shorten(t *testing.T, h http.Handler, original string) string {
+	req := httptest.NewRequest(http.MethodPost, "/api/shorten", bytes.NewBufferString(`{"url":"`+original+`"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var body struct {
+		Result string `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	return body.Result
+}
+
+func TestNew_IndependentInstances(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	firstRepo := repository.NewMemoryRepositories(storage.NewInMemoryStorage())
+	secondRepo := repository.NewMemoryRepositories(storage.NewInMemoryStorage())
+
+	first := newShortener(t, "http://first.local", firstRepo, fixedClock(now))
+	second := newShortener(t, "http://second.local", secondRepo, fixedClock(now))
+
+	short := shorten(t, first.Handler(), "https://hard2code.ru")
+	assert.True(t, strings.HasPrefix(short, "http://first.local/"))
+
+	hash := strings.TrimPrefix(short, "http://first.local/")
+	m, err := firstRepo.URLRepository().FindByHash(context.Background(), hash)
+	require.NoError(t, err)
+	assert.Equal(t, now, m.CreatedAt)
+
+	_, err = secondRepo.URLRepository().FindByHash(context.Background(), hash)
+	assert.Error(t, err)
+
+	short = shorten(t, second.Handler(), "https://hard2code.ru")
+	assert.True(t, strings.HasPrefix(short, "http://second.local/"))
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	cfg := shortener.DefaultConfig()
+	cfg.Address = "no-port"
+
+	_, err := shortener.New(cfg, shortener.WithLogger(nopLogger{}))
+	assert.Error(t, err)
+}